@@ -36,9 +36,13 @@ func main() {
 			"callerRtpAddr": "1.2.3.4:12345",
 			"serverRtpPort": 10000,
 		},
+		// Alan adları kontratın protojson (lowerCamelCase) gösterimine uyar.
 		"dialplanResolution": map[string]interface{}{
+			"dialplanId": "DP_DEMO_AI",
+			"tenantId":   "demo",
 			"action": map[string]string{
 				"action": "START_AI_CONVERSATION",
+				"type":   "ACTION_TYPE_START_AI_CONVERSATION",
 			},
 			"matchedUser": map[string]string{
 				"id":       "user-123",
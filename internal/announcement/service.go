@@ -0,0 +1,207 @@
+// Package announcement, sistem ve tenant anonslarının çözülmesi, TAS üzerinden
+// çalınması ve çalma bitişinin beklenmesinden sorumludur.
+package announcement
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+
+	"github.com/sentiric/sentiric-agent-service/internal/catalog"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+// ErrPlaybackTimeout, çalma bitiş olayı beklenen süre içinde gelmediğinde döner.
+var ErrPlaybackTimeout = errors.New("playback finished event not received in time")
+
+// ErrPlaybackUnobserved, anons çalındığı hâlde bitişi dinlenemediğinde döner; çağıran bitişi kendisi tahmin etmelidir.
+var ErrPlaybackUnobserved = errors.New("playback started but its finish cannot be observed")
+
+// Request, çalınacak anonsun çağrı bağlamıdır.
+type Request struct {
+	CallID       string
+	TenantID     string
+	LanguageCode string
+	ID           constants.AnnouncementID
+}
+
+type Service struct {
+	catalog     *catalog.Catalog
+	telephony   telephonyv1.TelephonyActionServiceClient
+	rdb         *redis.Client
+	waitTimeout time.Duration
+	log         zerolog.Logger
+}
+
+func NewService(catalog *catalog.Catalog, telephony telephonyv1.TelephonyActionServiceClient, rdb *redis.Client, waitTimeout time.Duration, log zerolog.Logger) *Service {
+	return &Service{
+		catalog:     catalog,
+		telephony:   telephony,
+		rdb:         rdb,
+		waitTimeout: waitTimeout,
+		log:         log,
+	}
+}
+
+// Resolve, anonsun ses dosyası yolunu tenant/dil/sistem sırasıyla çözer.
+func (s *Service) Resolve(ctx context.Context, req Request) (string, error) {
+	path, err := s.catalog.AnnouncementPath(ctx, req.ID, req.TenantID, req.LanguageCode)
+	if err != nil {
+		return "", fmt.Errorf("anons yolu çözülemedi (%s): %w", req.ID, err)
+	}
+	return path, nil
+}
+
+// Play, anonsu çaldırır ve bitişini beklemeden döner. Çalma yine de sıraya kaydedilir; böylece bitiş olayı
+// kendisinden sonra başlatılan ve beklenen bir anonsa yanlışlıkla eşlenmez.
+func (s *Service) Play(ctx context.Context, req Request) error {
+	path, err := s.Resolve(ctx, req)
+	if err != nil {
+		return err
+	}
+	id := newPlaybackID()
+	if err := s.register(ctx, req.CallID, id, path); err != nil {
+		s.log.Warn().Str("event", "PLAYBACK_REGISTER_FAILED").Str("call_id", req.CallID).Err(err).Msg("Çalma sıraya kaydedilemedi.")
+	}
+	if err := s.playPath(ctx, req, path); err != nil {
+		s.unregister(ctx, req.CallID, id, path)
+		return err
+	}
+	return nil
+}
+
+// PlayAndWait, anonsu çaldırır ve yalnızca bu çalmaya ait call.media.playback.finished olayını bekler.
+// Olay sözleşmede bir çalma kimliği taşımadığından her çalmaya bir kimlik verilir ve çağrının çalma sırasına
+// (playback:pending:<call_id>) eklenir; bitiş olayı sıradaki ilk çalmayla (olay ses yolunu taşıyorsa o yola
+// ait ilk çalmayla) eşlenir ve yalnızca onun kanalına duyurulur. Bitiş olayı başka bir replikaya düşebileceği
+// için bildirim Redis Pub/Sub ile alınır; abonelik, yarış durumunu önlemek için çalma isteğinden önce açılır.
+func (s *Service) PlayAndWait(ctx context.Context, req Request) error {
+	path, err := s.Resolve(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	id := newPlaybackID()
+	sub := s.rdb.Subscribe(ctx, playbackChannel(req.CallID, id))
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		s.log.Warn().Str("event", "PLAYBACK_SUBSCRIBE_FAILED").Str("call_id", req.CallID).Err(err).Msg("Çalma bitişi dinlenemiyor, anons beklenmeden devam edilecek.")
+		if err := s.Play(ctx, req); err != nil {
+			return err
+		}
+		return ErrPlaybackUnobserved
+	}
+
+	if err := s.register(ctx, req.CallID, id, path); err != nil {
+		s.log.Warn().Str("event", "PLAYBACK_REGISTER_FAILED").Str("call_id", req.CallID).Err(err).Msg("Çalma sıraya kaydedilemedi, anons beklenmeden devam edilecek.")
+		if err := s.playPath(ctx, req, path); err != nil {
+			return err
+		}
+		return ErrPlaybackUnobserved
+	}
+	if err := s.playPath(ctx, req, path); err != nil {
+		s.unregister(ctx, req.CallID, id, path)
+		return err
+	}
+
+	timer := time.NewTimer(s.waitTimeout)
+	defer timer.Stop()
+	select {
+	case <-sub.Channel():
+		return nil
+	case <-timer.C:
+		// Bitişi gelmeyen çalma sırada kalırsa sonraki bitiş olayları ona eşlenir.
+		s.unregister(context.Background(), req.CallID, id, path)
+		return ErrPlaybackTimeout
+	case <-ctx.Done():
+		s.unregister(context.Background(), req.CallID, id, path)
+		return ctx.Err()
+	}
+}
+
+// NotifyPlaybackFinished, çağrıdaki bitiş olayını sıradaki çalmayla eşler ve onu bekleyen replikaya duyurur.
+// audioURI boş değilse o yola ait ilk çalma seçilir; ondan önceki çalmaların bitişi kaçırılmış sayılıp sıradan düşer.
+// Eşlenen çalma yoksa (ör. pipeline'ın kendi sesi) olay yok sayılır.
+func (s *Service) NotifyPlaybackFinished(ctx context.Context, callID, audioURI string) error {
+	id, err := finishPlaybackScript.Run(ctx, s.rdb, []string{pendingKey(callID)}, audioURI).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.rdb.Publish(ctx, playbackChannel(callID, id), "finished").Err()
+}
+
+// pendingTTL, bir çağrının çalma sırasının en uzun ömrüdür; çağrı bitince sıra kendiliğinden silinir.
+const pendingTTL = 2 * time.Hour
+
+func (s *Service) register(ctx context.Context, callID, id, path string) error {
+	key := pendingKey(callID)
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, id+"|"+path)
+		pipe.Expire(ctx, key, pendingTTL)
+		return nil
+	})
+	return err
+}
+
+func (s *Service) unregister(ctx context.Context, callID, id, path string) {
+	opCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	_ = s.rdb.LRem(opCtx, pendingKey(callID), 1, id+"|"+path).Err()
+}
+
+// finishPlaybackScript, sıradaki ilk çalmayı (ARGV[1] doluysa o yola ait ilk çalmayı) ve ondan öncekileri
+// sıradan atomik olarak düşürür ve çalmanın kimliğini döner. Eşleşme yoksa sıra değişmez ve nil döner.
+var finishPlaybackScript = redis.NewScript(`
+local entries = redis.call('LRANGE', KEYS[1], 0, -1)
+for i, entry in ipairs(entries) do
+  local sep = string.find(entry, '|', 1, true)
+  if sep and (ARGV[1] == '' or string.sub(entry, sep + 1) == ARGV[1]) then
+    redis.call('LTRIM', KEYS[1], i, -1)
+    return string.sub(entry, 1, sep - 1)
+  end
+end
+return false
+`)
+
+func (s *Service) playPath(ctx context.Context, req Request, path string) error {
+	reqCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	resp, err := s.telephony.PlayAudio(reqCtx, &telephonyv1.PlayAudioRequest{
+		CallId:   req.CallID,
+		AudioUri: path,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("TAS anonsu çalamadı: %s", req.ID)
+	}
+
+	s.log.Debug().Str("event", "ANNOUNCEMENT_PLAYING").Str("call_id", req.CallID).Str("announcement_id", string(req.ID)).Str("audio_path", path).Msg("Anons çalınıyor.")
+	return nil
+}
+
+func playbackChannel(callID, playbackID string) string {
+	return "playback:finished:" + callID + ":" + playbackID
+}
+
+func pendingKey(callID string) string {
+	return "playback:pending:" + callID
+}
+
+func newPlaybackID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -6,20 +6,31 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	workspacev1 "github.com/sentiric/sentiric-agent-service/gen/go/sentiric/agent/v1"
+	"github.com/sentiric/sentiric-agent-service/internal/announcement"
+	"github.com/sentiric/sentiric-agent-service/internal/businesshours"
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/catalog"
 	"github.com/sentiric/sentiric-agent-service/internal/client"
 	"github.com/sentiric/sentiric-agent-service/internal/config"
 	"github.com/sentiric/sentiric-agent-service/internal/database"
 	"github.com/sentiric/sentiric-agent-service/internal/handler"
 	"github.com/sentiric/sentiric-agent-service/internal/metrics"
+	"github.com/sentiric/sentiric-agent-service/internal/presence"
+	"github.com/sentiric/sentiric-agent-service/internal/prompt"
 	"github.com/sentiric/sentiric-agent-service/internal/queue"
+	"github.com/sentiric/sentiric-agent-service/internal/queuestats"
 	"github.com/sentiric/sentiric-agent-service/internal/server"
 	"github.com/sentiric/sentiric-agent-service/internal/state"
+	"github.com/sentiric/sentiric-agent-service/internal/voicemodel"
+	"github.com/sentiric/sentiric-agent-service/migrations"
 	agentv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/agent/v1"
 )
 
@@ -50,25 +61,81 @@ func (a *App) Run() {
 
 	rmq := queue.NewRabbitMQ(a.Cfg.RabbitMQURL, a.Log)
 	stateMgr := state.NewManager(rdb)
+	dedupStore := state.NewDedupStore(rdb, time.Duration(a.Cfg.EventDedupWindowSeconds)*time.Second)
 
-	callHandler := handler.NewCallHandler(clients, stateMgr, rmq, db, a.Log)
-	eventHandler := handler.NewEventHandler(a.Log, metrics.EventsProcessed, metrics.EventsFailed, callHandler)
+	var wg sync.WaitGroup
+	// Şema, veritabanına ulaşılır ulaşılmaz gömülü migrations/ dosyalarıyla güncellenir. Veritabanına yazan
+	// tüketiciler (olaylar, gRPC, kuyruk dağıtıcısı) şema hazır olana kadar başlatılmaz.
+	schemaReady := make(chan struct{})
+	if a.Cfg.AutoMigrate {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := database.MigrateWhenReady(ctx, db, migrations.FS, 5*time.Second, metrics.MigrationFailures, a.Log); err == nil {
+				close(schemaReady)
+			}
+		}()
+	} else {
+		close(schemaReady)
+	}
+	transcripts := database.NewTranscriptWriter(db, a.Log, a.Cfg.TranscriptBatchSize,
+		time.Duration(a.Cfg.TranscriptFlushIntervalMs)*time.Millisecond, metrics.TranscriptsDropped)
+	transcripts.Start(ctx, &wg)
+
+	// Anons/şablon kataloğu: TTL'li önbellek, tablo değişikliklerinde LISTEN/NOTIFY ile anında boşaltılır.
+	catalogCache := catalog.NewCatalog(db, a.Cfg.DefaultLanguageCode, time.Duration(a.Cfg.CatalogCacheTTLSeconds)*time.Second, a.Log)
+	catalogCache.Listen(ctx, a.Cfg.PostgresURL, &wg)
+	catalogCache.ValidateWhenReady(ctx, a.Cfg.SupportedLanguages)
+
+	announcements := announcement.NewService(catalogCache, clients.TelephonyAction, rdb,
+		time.Duration(a.Cfg.AnnouncementWaitTimeoutSeconds)*time.Second, a.Log)
+
+	callQueue := callqueue.NewQueue(rdb, time.Duration(a.Cfg.QueuePriorityStepSeconds)*time.Second)
+	presenceStore := presence.NewStore(rdb, time.Duration(a.Cfg.PresenceTTLSeconds)*time.Second)
+	queueStats := queuestats.NewEngine(rdb, db, callQueue, presenceStore, queuestats.Gauges{
+		Waiting:      metrics.QueueWaiting,
+		LongestWait:  metrics.QueueLongestWait,
+		ASA:          metrics.QueueASA,
+		AbandonRate:  metrics.QueueAbandonRate,
+		ServiceLevel: metrics.QueueServiceLevel,
+		Agents:       metrics.AgentsByStatus,
+	}, time.Duration(a.Cfg.StatsRefreshSeconds)*time.Second, a.Log)
+	queueStats.Start(ctx, &wg)
+
+	businessHours := businesshours.NewChecker(db, a.Log)
+	callHandler := handler.NewCallHandler(a.Cfg, clients, stateMgr, rmq, db, transcripts,
+		voicemodel.NewSelector(db, stateMgr, a.Log), announcements,
+		prompt.NewEngine(catalogCache, businessHours, a.Cfg.Timezone), businessHours,
+		callQueue, presenceStore, queueStats, a.Log)
+	eventHandler := handler.NewEventHandler(a.Log, metrics.EventsProcessed, metrics.EventsFailed, metrics.EventsUnrouted, metrics.EventsDuplicateSkipped, dedupStore, callHandler)
 
 	grpcServer := server.NewGrpcServer(a.Cfg, a.Log)
 	agentv1.RegisterAgentOrchestrationServiceServer(grpcServer, &AgentServer{handler: callHandler})
+	workspacev1.RegisterAgentWorkspaceServiceServer(grpcServer, callHandler)
 
+	go metrics.StartServer(a.Cfg.MetricsPort, a.Log)
+
+	wg.Add(1)
 	go func() {
-		a.Log.Info().Str("event", "GRPC_SERVER_START").Msg("🚀 gRPC Server (Orchestration) active: 12031")
-		if err := server.Start(grpcServer, "12031"); err != nil && err.Error() != "http: Server closed" {
-			a.Log.Fatal().Str("event", "GRPC_SERVER_FAILED").Err(err).Msg("gRPC serve failed")
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			return
+		case <-schemaReady:
+		}
+		if ctx.Err() != nil {
+			return
 		}
+		callHandler.StartQueueDispatcher(ctx, &wg)
+		go func() {
+			a.Log.Info().Str("event", "GRPC_SERVER_START").Msg("🚀 gRPC Server (Orchestration) active: 12031")
+			if err := server.Start(grpcServer, "12031"); err != nil && err.Error() != "http: Server closed" {
+				a.Log.Fatal().Str("event", "GRPC_SERVER_FAILED").Err(err).Msg("gRPC serve failed")
+			}
+		}()
+		go rmq.Start(ctx, eventHandler.HandleRabbitMQMessage, &wg)
 	}()
 
-	go metrics.StartServer(a.Cfg.MetricsPort, a.Log)
-
-	var wg sync.WaitGroup
-	go rmq.Start(ctx, eventHandler.HandleRabbitMQMessage, &wg)
-
 	a.handleShutdown(cancel, grpcServer, &wg)
 }
 
@@ -109,7 +176,7 @@ func (s *AgentServer) ProcessSagaStep(ctx context.Context, req *agentv1.ProcessS
 }
 
 func (s *AgentServer) GetConversationTranscript(ctx context.Context, req *agentv1.GetConversationTranscriptRequest) (*agentv1.GetConversationTranscriptResponse, error) {
-	return &agentv1.GetConversationTranscriptResponse{}, nil
+	return s.handler.GetConversationTranscript(ctx, req)
 }
 
 func (s *AgentServer) ProcessManualDial(ctx context.Context, req *agentv1.ProcessManualDialRequest) (*agentv1.ProcessManualDialResponse, error) {
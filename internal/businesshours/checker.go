@@ -0,0 +1,183 @@
+// Package businesshours, tenant takvimine (mesai saatleri ve tatiller) göre bir anın
+// mesai içinde olup olmadığını ve mesai dışında uygulanacak aksiyonu belirler.
+package businesshours
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+)
+
+// DefaultScheduleID, dialplan bir takvim belirtmediğinde aranan tenant takvimidir.
+const DefaultScheduleID = "default"
+
+// Mesai dışı olma nedenleri.
+const (
+	ReasonClosed  = "CLOSED"
+	ReasonHoliday = "HOLIDAY"
+)
+
+// Status, takvim kontrolünün sonucudur. Open false ise Action ve AnnouncementID uygulanacak aksiyonu tanımlar.
+type Status struct {
+	Open           bool
+	ScheduleID     string
+	Timezone       string
+	Reason         string
+	HolidayName    string
+	Action         string
+	AnnouncementID string
+}
+
+type Checker struct {
+	db  *sql.DB
+	log zerolog.Logger
+}
+
+func NewChecker(db *sql.DB, log zerolog.Logger) *Checker {
+	return &Checker{db: db, log: log}
+}
+
+// Check, verilen anın takvime göre mesai içinde olup olmadığını döner. Tenant için takvim
+// tanımlı değilse çağrılar her zaman mesai içinde kabul edilir.
+func (c *Checker) Check(ctx context.Context, tenantID, scheduleID string, now time.Time) (*Status, error) {
+	if scheduleID == "" {
+		scheduleID = DefaultScheduleID
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	sched, err := database.GetBusinessSchedule(dbCtx, c.db, tenantID, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("takvim okunamadı (%s): %w", scheduleID, err)
+	}
+	if sched == nil {
+		return &Status{Open: true, ScheduleID: scheduleID}, nil
+	}
+
+	loc, err := time.LoadLocation(sched.Timezone)
+	if err != nil {
+		c.log.Warn().Str("event", "SCHEDULE_TIMEZONE_INVALID").Str("tenant_id", tenantID).Str("schedule_id", scheduleID).Str("timezone", sched.Timezone).Msg("Takvim saat dilimi geçersiz, UTC kullanılıyor.")
+		loc = time.UTC
+	}
+	local := now.In(loc)
+
+	st := &Status{
+		ScheduleID:     scheduleID,
+		Timezone:       loc.String(),
+		Action:         sched.OutOfHoursAction,
+		AnnouncementID: sched.OutOfHoursAnnouncementID,
+	}
+
+	// Dünden taşan gece vardiyası bugün tatil olsa da sürer; bu yüzden iki günün de geçerli aralıkları çözülür.
+	today, holiday, err := c.dayHours(dbCtx, tenantID, scheduleID, local)
+	if err != nil {
+		return nil, err
+	}
+	yesterday, _, err := c.dayHours(dbCtx, tenantID, scheduleID, local.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	st.Open = isOpen(today, yesterday, minuteOfDay(local))
+	if holiday != nil {
+		st.HolidayName = holiday.Name
+	}
+	if !st.Open {
+		st.Reason = closedReason(holiday)
+	}
+	return st, nil
+}
+
+// dayHours, günün geçerli mesai aralıklarını döner: gün tatilse tatil saatleri, değilse haftalık aralıklar.
+func (c *Checker) dayHours(ctx context.Context, tenantID, scheduleID string, day time.Time) ([]database.TimeRange, *database.Holiday, error) {
+	holiday, err := database.GetHoliday(ctx, c.db, tenantID, scheduleID, day.Format("2006-01-02"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("tatil takvimi okunamadı (%s): %w", scheduleID, err)
+	}
+	if holiday != nil {
+		return holidayHours(holiday), holiday, nil
+	}
+	weekly, err := database.GetBusinessHours(ctx, c.db, tenantID, scheduleID, int(day.Weekday()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("mesai saatleri okunamadı (%s): %w", scheduleID, err)
+	}
+	return weekly, nil, nil
+}
+
+// holidayHours, tatil gününün aralıklarıdır; saat verilmemiş tatil tamamen kapalıdır.
+func holidayHours(h *database.Holiday) []database.TimeRange {
+	if h.Hours == nil {
+		return nil
+	}
+	return []database.TimeRange{*h.Hours}
+}
+
+// closedReason, kapalı olma nedenini döner: bugün tatilse HOLIDAY, değilse CLOSED.
+func closedReason(holiday *database.Holiday) string {
+	if holiday != nil {
+		return ReasonHoliday
+	}
+	return ReasonClosed
+}
+
+// Location, tenant'ın varsayılan takviminin saat dilimini döner. Takvim tanımlı değilse veya saat dilimi
+// okunamıyorsa ok=false döner; çağıran kendi varsayılanını kullanır.
+func (c *Checker) Location(ctx context.Context, tenantID string) (*time.Location, bool) {
+	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	sched, err := database.GetBusinessSchedule(dbCtx, c.db, tenantID, DefaultScheduleID)
+	if err != nil || sched == nil {
+		return nil, false
+	}
+	loc, err := time.LoadLocation(sched.Timezone)
+	if err != nil {
+		return nil, false
+	}
+	return loc, true
+}
+
+// isOpen, dakikanın bugünkü aralıklardan birinde veya dünden taşan bir gece vardiyasında olup olmadığını döner.
+func isOpen(today, yesterday []database.TimeRange, minute int) bool {
+	for _, r := range today {
+		if withinRange(r, minute) {
+			return true
+		}
+	}
+	for _, r := range yesterday {
+		open, close := parseClock(r.Open), parseClock(r.Close)
+		if close <= open && minute < close {
+			return true
+		}
+	}
+	return false
+}
+
+// withinRange, dakikanın aralığın bugüne düşen kısmında olup olmadığını döner.
+// Kapanışı açılıştan önce olan aralık gece yarısını aşar; bugün için açılıştan gün sonuna kadar geçerlidir.
+func withinRange(r database.TimeRange, minute int) bool {
+	open, close := parseClock(r.Open), parseClock(r.Close)
+	if close > open {
+		return minute >= open && minute < close
+	}
+	return minute >= open
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// parseClock, "HH:MM" değerini günün dakikasına çevirir.
+func parseClock(v string) int {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0
+	}
+	return t.Hour()*60 + t.Minute()
+}
@@ -0,0 +1,88 @@
+package businesshours
+
+import (
+	"testing"
+
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+)
+
+func clock(h, m int) int { return h*60 + m }
+
+func TestWithinRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      database.TimeRange
+		minute int
+		want   bool
+	}{
+		{"inside day range", database.TimeRange{Open: "09:00", Close: "18:00"}, clock(12, 0), true},
+		{"at opening", database.TimeRange{Open: "09:00", Close: "18:00"}, clock(9, 0), true},
+		{"at closing is closed", database.TimeRange{Open: "09:00", Close: "18:00"}, clock(18, 0), false},
+		{"before opening", database.TimeRange{Open: "09:00", Close: "18:00"}, clock(8, 59), false},
+		{"overnight evening part", database.TimeRange{Open: "22:00", Close: "06:00"}, clock(23, 30), true},
+		{"overnight morning part belongs to next day", database.TimeRange{Open: "22:00", Close: "06:00"}, clock(2, 0), false},
+		{"overnight before opening", database.TimeRange{Open: "22:00", Close: "06:00"}, clock(21, 59), false},
+		{"open equals close spans whole evening", database.TimeRange{Open: "00:00", Close: "00:00"}, clock(0, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinRange(tt.r, tt.minute); got != tt.want {
+				t.Errorf("withinRange(%v, %d) = %v, want %v", tt.r, tt.minute, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOpen(t *testing.T) {
+	day := []database.TimeRange{{Open: "09:00", Close: "18:00"}}
+	night := []database.TimeRange{{Open: "22:00", Close: "06:00"}}
+	closedHoliday := holidayHours(&database.Holiday{Name: "Yılbaşı"})
+	shortHoliday := holidayHours(&database.Holiday{Name: "Arife", Hours: &database.TimeRange{Open: "09:00", Close: "13:00"}})
+
+	tests := []struct {
+		name      string
+		today     []database.TimeRange
+		yesterday []database.TimeRange
+		minute    int
+		want      bool
+	}{
+		{"regular day inside hours", day, day, clock(10, 0), true},
+		{"regular day after hours", day, day, clock(19, 0), false},
+		{"overnight shift started today", night, nil, clock(23, 0), true},
+		{"overnight shift spilling from yesterday", nil, night, clock(5, 59), true},
+		{"overnight shift ended", nil, night, clock(6, 0), false},
+		{"closed holiday still honours yesterday's overnight shift", closedHoliday, night, clock(2, 0), true},
+		{"closed holiday after yesterday's shift ends", closedHoliday, night, clock(7, 0), false},
+		{"closed holiday without overnight shift", closedHoliday, day, clock(10, 0), false},
+		{"holiday with reduced hours inside", shortHoliday, day, clock(12, 0), true},
+		{"holiday with reduced hours outside", shortHoliday, day, clock(14, 0), false},
+		{"day after a closed holiday ignores the weekly overnight shift", day, closedHoliday, clock(2, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOpen(tt.today, tt.yesterday, tt.minute); got != tt.want {
+				t.Errorf("isOpen(%d) = %v, want %v", tt.minute, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosedReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		holiday *database.Holiday
+		want    string
+	}{
+		{"regular day", nil, ReasonClosed},
+		{"holiday", &database.Holiday{Name: "Yılbaşı"}, ReasonHoliday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closedReason(tt.holiday); got != tt.want {
+				t.Errorf("closedReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package callqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// OfferTTL, cevaplanmamış bir teklifin Redis'te tutulduğu en uzun süredir.
+const OfferTTL = 10 * time.Minute
+
+// Offer, kuyruktan alınıp bir ajana teklif edilen üyedir. Ajan kabul edene kadar üye kuyrukta değildir;
+// reddedilirse Score ile eski sırasına döner.
+type Offer struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenantId"`
+	AgentID   string    `json:"agentId"`
+	Entry     Entry     `json:"entry"`
+	Score     float64   `json:"score"`
+	OfferedAt time.Time `json:"offeredAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Revoked, zaman aşımıyla geri çekilen teklifin ajana bildirimidir.
+	Revoked bool `json:"revoked,omitempty"`
+}
+
+// OfferRef, süresi dolan bir teklifi tanımlar.
+type OfferRef struct {
+	TenantID string
+	AgentID  string
+	OfferID  string
+}
+
+func offerKey(tenantID, agentID, offerID string) string {
+	return "queue:offer:" + tenantID + ":" + agentID + ":" + offerID
+}
+
+// pendingOffersKey, cevap bekleyen teklifleri "tenant|agent|offer" üyesi ve bitiş zamanı skoruyla tutar.
+const pendingOffersKey = "queue:offers:pending"
+
+func offerChannel(tenantID, agentID string) string { return "queue:offers:" + tenantID + ":" + agentID }
+
+// PublishOffer, teklifi saklar ve ajanın oturum kanalına yayınlar. Kanalı dinleyen oturum sayısını döner;
+// sıfırsa teklif hiçbir ajana ulaşmamıştır.
+func (q *Queue) PublishOffer(ctx context.Context, o Offer) (int64, error) {
+	val, err := json.Marshal(o)
+	if err != nil {
+		return 0, err
+	}
+	_, err = q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, offerKey(o.TenantID, o.AgentID, o.ID), val, OfferTTL)
+		pipe.ZAdd(ctx, pendingOffersKey, &redis.Z{
+			Score:  float64(o.ExpiresAt.UnixMilli()),
+			Member: o.TenantID + "|" + o.AgentID + "|" + o.ID,
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return q.rdb.Publish(ctx, offerChannel(o.TenantID, o.AgentID), val).Result()
+}
+
+// NotifyOfferRevoked, geri çekilen teklifi ajanın oturumuna bildirir (çalmayı durdurması için).
+func (q *Queue) NotifyOfferRevoked(ctx context.Context, o Offer) error {
+	o.Revoked = true
+	val, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return q.rdb.Publish(ctx, offerChannel(o.TenantID, o.AgentID), val).Err()
+}
+
+// DueOffers, cevap süresi dolmuş teklifleri döner.
+func (q *Queue) DueOffers(ctx context.Context, now time.Time) ([]OfferRef, error) {
+	members, err := q.rdb.ZRangeByScore(ctx, pendingOffersKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]OfferRef, 0, len(members))
+	for _, m := range members {
+		parts := strings.SplitN(m, "|", 3)
+		if len(parts) != 3 {
+			q.rdb.ZRem(ctx, pendingOffersKey, m)
+			continue
+		}
+		refs = append(refs, OfferRef{TenantID: parts[0], AgentID: parts[1], OfferID: parts[2]})
+	}
+	return refs, nil
+}
+
+// TakeOffer, ajanın teklifini atomik olarak alır; teklif yoksa (cevaplanmış, süresi dolmuş) nil döner.
+// Kabul, ret ve zaman aşımı aynı teklifi yalnızca bir kez sahiplenebilir.
+func (q *Queue) TakeOffer(ctx context.Context, tenantID, agentID, offerID string) (*Offer, error) {
+	q.rdb.ZRem(ctx, pendingOffersKey, tenantID+"|"+agentID+"|"+offerID)
+	val, err := q.rdb.GetDel(ctx, offerKey(tenantID, agentID, offerID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var o Offer
+	if err := json.Unmarshal([]byte(val), &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// SubscribeOffers, ajanın tekliflerini dinleyen bir abonelik açar.
+func (q *Queue) SubscribeOffers(ctx context.Context, tenantID, agentID string) *redis.PubSub {
+	return q.rdb.Subscribe(ctx, offerChannel(tenantID, agentID))
+}
+
+// DecodeOffer, yayın kanalından gelen teklifi çözer.
+func DecodeOffer(payload string) (*Offer, error) {
+	var o Offer
+	if err := json.Unmarshal([]byte(payload), &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
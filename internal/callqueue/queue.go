@@ -0,0 +1,406 @@
+// Package callqueue, tenant kuyruklarını Redis sorted set'leri üzerinde tutar. Kuyruktaki her üye
+// canlı bir çağrı ya da sırası korunmuş bir geri arama talebidir; sıralama skora (önceliğe göre öne çekilmiş
+// giriş zamanı) göredir.
+package callqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Kuyruk üyesi türleri.
+const (
+	KindCall     = "call"
+	KindCallback = "callback"
+)
+
+// DefaultQueueID, dialplan queue_id belirtmediğinde kullanılan kuyruktur.
+const DefaultQueueID = "default"
+
+// MaxPriority, bir üyeye verilebilecek en yüksek önceliktir.
+const MaxPriority = 10
+
+// EntryTTL, kuyruk üyesi meta verisinin en uzun yaşam süresidir (ertesi güne kalan geri aramalar dahil).
+const EntryTTL = 48 * time.Hour
+
+// Entry, kuyruktaki bir üyedir. ID, sorted set üyesidir: canlı çağrılarda çağrı kimliği,
+// geri aramalarda "callback:<id>".
+type Entry struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	TenantID   string    `json:"tenantId"`
+	QueueID    string    `json:"queueId"`
+	CallID     string    `json:"callId,omitempty"`
+	FromURI    string    `json:"fromUri"`
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	Attempts   int       `json:"attempts,omitempty"`
+	// Priority, 0 (normal) ile MaxPriority arasındaki önceliktir; her seviye üyeyi bir öncelik adımı kadar öne alır.
+	Priority int `json:"priority,omitempty"`
+	// Language, üyenin konuşma dilidir; dil de bir yetenek olarak şartlara eklenir.
+	Language   string            `json:"language,omitempty"`
+	ActionData map[string]string `json:"actionData,omitempty"`
+	// Deadline, azami bekleme süresi tanımlı üyenin bekleme sınırıdır (EnqueuedAt + azami bekleme); sıfırsa sınır yoktur.
+	Deadline time.Time `json:"deadline,omitempty"`
+}
+
+// Ref, bir tenant kuyruğunu tanımlar.
+type Ref struct {
+	TenantID string
+	QueueID  string
+}
+
+type Queue struct {
+	rdb          *redis.Client
+	priorityStep time.Duration
+}
+
+// NewQueue, her öncelik seviyesinin üyeyi priorityStep kadar öne aldığı bir kuyruk oluşturur.
+func NewQueue(rdb *redis.Client, priorityStep time.Duration) *Queue {
+	return &Queue{rdb: rdb, priorityStep: priorityStep}
+}
+
+func queueKey(tenantID, queueID string) string { return "queue:" + tenantID + ":" + queueID }
+func entryKey(id string) string                { return "queue:entry:" + id }
+
+// arrivalsKey, kuyruğun üyelerini giriş zamanı skoruyla tutar. Sıralama skoru önceliğe göre kaydırıldığından
+// en uzun bekleyen üye buradan okunur.
+func arrivalsKey(tenantID, queueID string) string {
+	return "queue:arrivals:" + tenantID + ":" + queueID
+}
+
+const activeKey = "queue:active"
+
+// deadlinesKey, azami bekleme süresi olan üyeleri bekleme sınırı skoruyla tutar; dağıtıcı her turda süresi dolanları işler.
+const deadlinesKey = "queue:deadlines"
+
+// ClampPriority, önceliği 0..MaxPriority aralığına sınırlar.
+func ClampPriority(p int) int {
+	return min(max(p, 0), MaxPriority)
+}
+
+// Score, üyenin kuyruktaki sıralama değeridir: giriş zamanı, her öncelik seviyesi için bir öncelik adımı
+// kadar geriye çekilir. Böylece yüksek öncelikli üye öne geçer, ama adım × seviye farkından uzun bekleyen
+// düşük öncelikli üye ondan önce hizmet alır (aging); hiçbir üye sonsuza dek geride kalmaz.
+func (q *Queue) Score(e Entry) float64 {
+	return float64(e.EnqueuedAt.UnixMilli() - int64(ClampPriority(e.Priority))*q.priorityStep.Milliseconds())
+}
+
+// Enqueue, üyeyi kuyruğa ekler ve 1'den başlayan sırasını döner. Üye zaten kuyruktaysa sırası korunur.
+func (q *Queue) Enqueue(ctx context.Context, e Entry) (int64, error) {
+	return q.add(ctx, e, q.Score(e))
+}
+
+func (q *Queue) add(ctx context.Context, e Entry, score float64) (int64, error) {
+	val, err := json.Marshal(e)
+	if err != nil {
+		return 0, err
+	}
+	key := queueKey(e.TenantID, e.QueueID)
+	_, err = q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, entryKey(e.ID), val, EntryTTL)
+		pipe.ZAddNX(ctx, key, &redis.Z{Score: score, Member: e.ID})
+		pipe.ZAddNX(ctx, arrivalsKey(e.TenantID, e.QueueID), &redis.Z{Score: float64(e.EnqueuedAt.UnixMilli()), Member: e.ID})
+		pipe.SAdd(ctx, activeKey, e.TenantID+"|"+e.QueueID)
+		if !e.Deadline.IsZero() {
+			pipe.ZAdd(ctx, deadlinesKey, &redis.Z{Score: float64(e.Deadline.UnixMilli()), Member: e.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return q.Position(ctx, e.TenantID, e.QueueID, e.ID)
+}
+
+// Replace, kuyruktaki bir üyenin yerine yenisini aynı skorla koyar (örn. çağrı → geri arama);
+// böylece yeni üye eskisinin sırasını devralır. Eski üye kuyrukta değilse false döner.
+func (q *Queue) Replace(ctx context.Context, oldID string, e Entry) (int64, bool, error) {
+	key := queueKey(e.TenantID, e.QueueID)
+	score, err := q.rdb.ZScore(ctx, key, oldID).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	removed, err := q.rdb.ZRem(ctx, key, oldID).Result()
+	if err != nil || removed == 0 {
+		// Üye bu arada bir ajana atanmış olabilir.
+		return 0, false, err
+	}
+	// Yeni üye aksiyon verisi taşımıyorsa (ör. ajan masaüstünden istenen geri arama) eskisininkini
+	// devralır; böylece yetenek şartları ve zaman aşımları kaybolmaz. Skor korunduğundan öncelik de devralınır.
+	if old, err := q.Get(ctx, oldID); err == nil && old != nil {
+		if e.ActionData == nil {
+			e.ActionData = old.ActionData
+		}
+		e.Priority = max(e.Priority, old.Priority)
+	}
+	q.rdb.Del(ctx, entryKey(oldID))
+	q.rdb.ZRem(ctx, deadlinesKey, oldID)
+	q.rdb.ZRem(ctx, arrivalsKey(e.TenantID, e.QueueID), oldID)
+
+	pos, err := q.add(ctx, e, score)
+	return pos, err == nil, err
+}
+
+// Requeue, dağıtımı başarısız olan üyeyi önceki skoruyla kuyruğa geri koyar.
+func (q *Queue) Requeue(ctx context.Context, e Entry, score float64) error {
+	_, err := q.add(ctx, e, score)
+	return err
+}
+
+// Get, üyenin meta verisini döner; üye yoksa nil döner.
+func (q *Queue) Get(ctx context.Context, id string) (*Entry, error) {
+	val, err := q.rdb.Get(ctx, entryKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal([]byte(val), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Remove, üyeyi kuyruğundan çıkarır. Üyeyi bu çağrı çıkardıysa true döner; böylece aynı üyeyi
+// eşzamanlı çıkarmaya çalışan (ör. iki replikadaki dağıtıcı) yalnızca bir taraf sahiplenir.
+func (q *Queue) Remove(ctx context.Context, id string) (*Entry, bool, error) {
+	e, err := q.Get(ctx, id)
+	if err != nil || e == nil {
+		return nil, false, err
+	}
+	removed, err := q.rdb.ZRem(ctx, queueKey(e.TenantID, e.QueueID), id).Result()
+	if err != nil {
+		return e, false, err
+	}
+	q.rdb.Del(ctx, entryKey(id))
+	q.rdb.ZRem(ctx, deadlinesKey, id)
+	q.rdb.ZRem(ctx, arrivalsKey(e.TenantID, e.QueueID), id)
+	return e, removed > 0, nil
+}
+
+// DueDeadlines, bekleme sınırı dolmuş üyelerin kimliklerini döner.
+func (q *Queue) DueDeadlines(ctx context.Context, now time.Time) ([]string, error) {
+	return q.rdb.ZRangeByScore(ctx, deadlinesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+}
+
+// TakeDeadline, üyenin bekleme sınırını atomik olarak sahiplenir; sınırı başka bir replika işlediyse false döner.
+func (q *Queue) TakeDeadline(ctx context.Context, id string) (bool, error) {
+	n, err := q.rdb.ZRem(ctx, deadlinesKey, id).Result()
+	return n > 0, err
+}
+
+// Position, üyenin kuyruktaki 1'den başlayan sırasını döner; üye kuyrukta değilse 0 döner.
+func (q *Queue) Position(ctx context.Context, tenantID, queueID, id string) (int64, error) {
+	rank, err := q.rdb.ZRank(ctx, queueKey(tenantID, queueID), id).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rank + 1, nil
+}
+
+// Placement, bir üyenin kuyruktaki yeri ve bu yeri belirleyen öncelik bilgisidir.
+type Placement struct {
+	Entry    Entry
+	Position int64
+	Length   int64
+	Score    float64
+	// Waited, üyenin kuyruğa girdiğinden beri geçen süredir.
+	Waited time.Duration
+	// AgedLevels, beklemenin kazandırdığı öncelik seviyesidir: Waited / öncelik adımı. Üye, önceliği
+	// Priority + AgedLevels'tan düşük olan daha yeni üyelerin önündedir.
+	AgedLevels int
+}
+
+// Locate, üyenin kuyruktaki yerini döner; üye kuyrukta değilse nil döner.
+func (q *Queue) Locate(ctx context.Context, id string) (*Placement, error) {
+	e, err := q.Get(ctx, id)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	key := queueKey(e.TenantID, e.QueueID)
+	pipe := q.rdb.Pipeline()
+	rank := pipe.ZRank(ctx, key, id)
+	score := pipe.ZScore(ctx, key, id)
+	length := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	p := &Placement{
+		Entry:    *e,
+		Position: rank.Val() + 1,
+		Length:   length.Val(),
+		Score:    score.Val(),
+		Waited:   time.Since(e.EnqueuedAt),
+	}
+	p.AgedLevels = q.agedLevels(p.Waited)
+	return p, nil
+}
+
+// agedLevels, beklemenin kazandırdığı öncelik seviyesidir; öncelik adımı yoksa bekleme öncelik kazandırmaz.
+func (q *Queue) agedLevels(waited time.Duration) int {
+	if q.priorityStep <= 0 || waited <= 0 {
+		return 0
+	}
+	return int(waited / q.priorityStep)
+}
+
+// Waiting, kuyruktaki bir üye ve sıralama skorudur.
+type Waiting struct {
+	Entry Entry
+	Score float64
+}
+
+// Peek, kuyruğun başındaki en fazla n üyeyi sırasıyla döner. Meta verisi düşmüş yetim üyeler
+// kuyruğu tıkamaması için temizlenir.
+func (q *Queue) Peek(ctx context.Context, tenantID, queueID string, n int64) ([]Waiting, error) {
+	key := queueKey(tenantID, queueID)
+	zs, err := q.rdb.ZRangeWithScores(ctx, key, 0, n-1).Result()
+	if err != nil || len(zs) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(zs))
+	for i, z := range zs {
+		id, _ := z.Member.(string)
+		keys[i] = entryKey(id)
+	}
+	vals, err := q.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	waiting := make([]Waiting, 0, len(zs))
+	var stale []interface{}
+	for i, v := range vals {
+		raw, ok := v.(string)
+		var e Entry
+		if !ok || json.Unmarshal([]byte(raw), &e) != nil {
+			stale = append(stale, zs[i].Member)
+			continue
+		}
+		waiting = append(waiting, Waiting{Entry: e, Score: zs[i].Score})
+	}
+	// Kaydı olmayan üye, bekleme sınırı ve giriş zamanıyla birlikte temizlenir; aksi halde en uzun bekleme ve
+	// zaman aşımı taraması hayalet üyeleri görmeye devam eder.
+	if len(stale) > 0 {
+		pipe := q.rdb.Pipeline()
+		pipe.ZRem(ctx, key, stale...)
+		pipe.ZRem(ctx, arrivalsKey(tenantID, queueID), stale...)
+		pipe.ZRem(ctx, deadlinesKey, stale...)
+		_, _ = pipe.Exec(ctx)
+	}
+	return waiting, nil
+}
+
+// Backlog, kuyruktaki üye sayısını ve en uzun bekleyen üyenin giriş zamanını döner. Skor önceliğe göre
+// kaydırıldığından en eski üye giriş zamanı kümesinin başından okunur.
+func (q *Queue) Backlog(ctx context.Context, tenantID, queueID string) (int64, time.Time, error) {
+	pipe := q.rdb.Pipeline()
+	length := pipe.ZCard(ctx, queueKey(tenantID, queueID))
+	oldest := pipe.ZRangeWithScores(ctx, arrivalsKey(tenantID, queueID), 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+	if length.Val() == 0 || len(oldest.Val()) == 0 {
+		return length.Val(), time.Time{}, nil
+	}
+	return length.Val(), time.UnixMilli(int64(oldest.Val()[0].Score)), nil
+}
+
+// Length, kuyruktaki üye sayısını döner.
+func (q *Queue) Length(ctx context.Context, tenantID, queueID string) (int64, error) {
+	return q.rdb.ZCard(ctx, queueKey(tenantID, queueID)).Result()
+}
+
+// ActiveQueues, en az bir kez üye almış ve henüz boşalmamış kuyrukları döner.
+func (q *Queue) ActiveQueues(ctx context.Context) ([]Ref, error) {
+	members, err := q.rdb.SMembers(ctx, activeKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]Ref, 0, len(members))
+	for _, m := range members {
+		tenantID, queueID, ok := strings.Cut(m, "|")
+		if ok {
+			refs = append(refs, Ref{TenantID: tenantID, QueueID: queueID})
+		}
+	}
+	return refs, nil
+}
+
+// deactivateScript, kuyruk boşsa onu aktif listeden atomik olarak çıkarır; kontrol ile silme
+// arasında gelen bir Enqueue'nun kuyruğu listeden düşürmesini önler.
+var deactivateScript = redis.NewScript(`
+if redis.call("ZCARD", KEYS[1]) == 0 then
+	return redis.call("SREM", KEYS[2], ARGV[1])
+end
+return 0`)
+
+// Deactivate, boşalan kuyruğu dağıtıcının tarama listesinden çıkarır.
+func (q *Queue) Deactivate(ctx context.Context, ref Ref) error {
+	keys := []string{queueKey(ref.TenantID, ref.QueueID), activeKey}
+	return deactivateScript.Run(ctx, q.rdb, keys, ref.TenantID+"|"+ref.QueueID).Err()
+}
+
+func lockKey(ref Ref) string { return "queue:lock:" + ref.TenantID + ":" + ref.QueueID }
+
+// TryLock, bir kuyruğun dağıtımını kısa süreliğine tek replikaya ayırır. Kilit rastgele bir jetonla alınır;
+// Unlock ve ExtendLock yalnızca bu jetonla çalışır, böylece süresi dolup başka replikaya geçmiş kilit silinmez.
+func (q *Queue) TryLock(ctx context.Context, ref Ref, ttl time.Duration) (string, bool, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(b[:])
+	ok, err := q.rdb.SetNX(ctx, lockKey(ref), token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// unlockScript, kilidi yalnızca hâlâ verilen jetona aitse siler.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// extendLockScript, kilidin süresini yalnızca hâlâ verilen jetona aitse uzatır.
+var extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Unlock, TryLock ile alınan kilidi bırakır.
+func (q *Queue) Unlock(ctx context.Context, ref Ref, token string) error {
+	return unlockScript.Run(ctx, q.rdb, []string{lockKey(ref)}, token).Err()
+}
+
+// ExtendLock, kilidin süresini ttl'ye uzatır; kilit artık bu jetona ait değilse false döner.
+func (q *Queue) ExtendLock(ctx context.Context, ref Ref, token string, ttl time.Duration) (bool, error) {
+	n, err := extendLockScript.Run(ctx, q.rdb, []string{lockKey(ref)}, token, ttl.Milliseconds()).Int()
+	return n == 1, err
+}
@@ -0,0 +1,94 @@
+package callqueue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClampPriority(t *testing.T) {
+	tests := []struct {
+		in, want int
+	}{
+		{-3, 0},
+		{0, 0},
+		{5, 5},
+		{MaxPriority, MaxPriority},
+		{MaxPriority + 1, MaxPriority},
+	}
+
+	for _, tt := range tests {
+		if got := ClampPriority(tt.in); got != tt.want {
+			t.Errorf("ClampPriority(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	q := NewQueue(nil, time.Minute)
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		e    Entry
+		want time.Time
+	}{
+		{"normal priority keeps the enqueue time", Entry{EnqueuedAt: base}, base},
+		{"each level moves the entry one step ahead", Entry{EnqueuedAt: base, Priority: 5}, base.Add(-5 * time.Minute)},
+		{"priority above the maximum is clamped", Entry{EnqueuedAt: base, Priority: 99}, base.Add(-MaxPriority * time.Minute)},
+		{"negative priority is clamped", Entry{EnqueuedAt: base, Priority: -2}, base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := q.Score(tt.e), float64(tt.want.UnixMilli()); got != want {
+				t.Errorf("Score() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestScoreAging(t *testing.T) {
+	q := NewQueue(nil, time.Minute)
+	vip := Entry{EnqueuedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), Priority: 3}
+
+	tests := []struct {
+		name        string
+		normalAhead time.Duration
+		wantFirst   bool
+	}{
+		{"newer normal entry waits behind vip", -time.Minute, false},
+		{"normal entry that waited less than the priority gap waits", 2 * time.Minute, false},
+		{"normal entry that waited longer than the priority gap is served first", 4 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normal := Entry{EnqueuedAt: vip.EnqueuedAt.Add(-tt.normalAhead)}
+			if first := q.Score(normal) < q.Score(vip); first != tt.wantFirst {
+				t.Errorf("normal entry first = %v, want %v", first, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestAgedLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		step   time.Duration
+		waited time.Duration
+		want   int
+	}{
+		{"aging disabled", 0, time.Hour, 0},
+		{"not yet one step", time.Minute, 59 * time.Second, 0},
+		{"whole steps only", time.Minute, 150 * time.Second, 2},
+		{"clock skew does not age", time.Minute, -time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewQueue(nil, tt.step).agedLevels(tt.waited); got != tt.want {
+				t.Errorf("agedLevels(%v) = %d, want %d", tt.waited, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package catalog, anons ve prompt şablonu kayıtlarını TTL'li bir bellek önbelleğinde tutar.
+// Tablolardaki değişiklikler PostgreSQL LISTEN/NOTIFY ile bildirilir ve ilgili önbellek anında boşaltılır.
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+)
+
+// NotifyChannel, katalog tablolarındaki trigger'ların yayın yaptığı kanaldır (migrations/0003).
+const NotifyChannel = "catalog_changed"
+
+const (
+	kindAnnouncement = "announcements"
+	kindTemplate     = "templates"
+)
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+type Catalog struct {
+	db              *sql.DB
+	defaultLanguage string
+	ttl             time.Duration
+	log             zerolog.Logger
+
+	mu      sync.RWMutex
+	entries map[string]map[string]entry // tür → anahtar → kayıt
+}
+
+func NewCatalog(db *sql.DB, defaultLanguage string, ttl time.Duration, log zerolog.Logger) *Catalog {
+	return &Catalog{
+		db:              db,
+		defaultLanguage: defaultLanguage,
+		ttl:             ttl,
+		log:             log,
+		entries: map[string]map[string]entry{
+			kindAnnouncement: {},
+			kindTemplate:     {},
+		},
+	}
+}
+
+// AnnouncementPath, anonsun ses dosyası yolunu önbellekten veya veritabanından çözer.
+func (c *Catalog) AnnouncementPath(ctx context.Context, id constants.AnnouncementID, tenantID, languageCode string) (string, error) {
+	return c.lookup(ctx, kindAnnouncement, string(id), tenantID, languageCode, database.ResolveAnnouncementPath)
+}
+
+// TemplateContent, prompt şablonunun içeriğini önbellekten veya veritabanından çözer.
+func (c *Catalog) TemplateContent(ctx context.Context, id constants.TemplateID, tenantID, languageCode string) (string, error) {
+	return c.lookup(ctx, kindTemplate, string(id), tenantID, languageCode, database.ResolveTemplateContent)
+}
+
+type resolveFunc func(ctx context.Context, db *sql.DB, id, tenantID, languageCode, defaultLanguage string) (string, error)
+
+func (c *Catalog) lookup(ctx context.Context, kind, id, tenantID, languageCode string, resolve resolveFunc) (string, error) {
+	key := strings.Join([]string{id, tenantID, languageCode}, "|")
+
+	c.mu.RLock()
+	e, ok := c.entries[kind][key]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(e.expiresAt) {
+		return e.value, nil
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	value, err := resolve(dbCtx, c.db, id, tenantID, languageCode, c.defaultLanguage)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[kind][key] = entry{value: value, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// Invalidate, verilen tablonun önbelleğini boşaltır. Tablo tanınmıyorsa tüm katalog boşaltılır.
+func (c *Catalog) Invalidate(table string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[table]; ok {
+		c.entries[table] = map[string]entry{}
+		return
+	}
+	for kind := range c.entries {
+		c.entries[kind] = map[string]entry{}
+	}
+}
+
+// Listen, ayrı bir PostgreSQL bağlantısı üzerinden NotifyChannel'ı dinler ve gelen her bildirimde
+// önbelleği boşaltır. Bağlantı koparsa yeniden bağlanır; bu arada kaçırılan değişiklikler için
+// yeniden bağlanınca tüm önbellek boşaltılır. Not: LISTEN, transaction modundaki bir pooler arkasında çalışmaz.
+func (c *Catalog) Listen(ctx context.Context, postgresURL string, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			err := c.listenOnce(ctx, postgresURL)
+			if ctx.Err() != nil {
+				return
+			}
+			c.log.Warn().Str("event", "CATALOG_LISTEN_RETRY").Err(err).Msg("Katalog bildirim bağlantısı koptu, 5 saniye sonra tekrar denenecek...")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+}
+
+func (c *Catalog) listenOnce(ctx context.Context, postgresURL string) error {
+	connCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	conn, err := pgx.Connect(connCtx, postgresURL)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+NotifyChannel); err != nil {
+		return err
+	}
+	c.Invalidate("")
+	c.log.Info().Str("event", "CATALOG_LISTENING").Msg("📚 Anons/şablon katalog değişiklikleri dinleniyor.")
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		c.Invalidate(n.Payload)
+		c.log.Info().Str("event", "CATALOG_INVALIDATED").Str("table", n.Payload).Msg("Katalog değişti, önbellek yenilenecek.")
+	}
+}
+
+// Validate, her AnnouncementID ve TemplateID'nin desteklenen her dil için 'system' tenant'ında
+// tanımlı olduğunu kontrol eder. Eksikler yalnızca loglanır; servis açılmaya devam eder.
+func (c *Catalog) Validate(ctx context.Context, languages []string) (int, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	announcements, err := database.GetSystemAnnouncementLanguages(dbCtx, c.db)
+	if err != nil {
+		return 0, fmt.Errorf("anons kataloğu okunamadı: %w", err)
+	}
+	templates, err := database.GetSystemTemplateLanguages(dbCtx, c.db)
+	if err != nil {
+		return 0, fmt.Errorf("şablon kataloğu okunamadı: %w", err)
+	}
+
+	missing := 0
+	report := func(kind, id, lang string) {
+		missing++
+		c.log.Warn().Str("event", "CATALOG_ENTRY_MISSING").Str("table", kind).Str("id", id).Str("language_code", lang).Msg("⚠️ Sistem kataloğunda eksik kayıt.")
+	}
+	for _, lang := range languages {
+		for _, id := range constants.AnnouncementIDs {
+			if !announcements[string(id)][lang] {
+				report(kindAnnouncement, string(id), lang)
+			}
+		}
+		for _, id := range constants.TemplateIDs {
+			if !templates[string(id)][lang] {
+				report(kindTemplate, string(id), lang)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// ValidateWhenReady, veritabanı erişilebilir olana kadar bekleyip katalog doğrulamasını bir kez çalıştırır.
+func (c *Catalog) ValidateWhenReady(ctx context.Context, languages []string) {
+	go func() {
+		for {
+			missing, err := c.Validate(ctx, languages)
+			if err == nil {
+				if missing == 0 {
+					c.log.Info().Str("event", "CATALOG_VALIDATED").Strs("languages", languages).Msg("✅ Sistem anons/şablon kataloğu eksiksiz.")
+				} else {
+					c.log.Warn().Str("event", "CATALOG_INCOMPLETE").Int("missing", missing).Strs("languages", languages).Msg("⚠️ Sistem kataloğunda eksik kayıtlar var.")
+				}
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			c.log.Warn().Str("event", "CATALOG_VALIDATE_RETRY").Err(err).Msg("Katalog doğrulanamadı, 10 saniye sonra tekrar denenecek...")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(10 * time.Second):
+			}
+		}
+	}()
+}
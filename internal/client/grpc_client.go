@@ -11,6 +11,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/sentiric/sentiric-agent-service/internal/config"
+	mediav1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/media/v1"
 	sipv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/sip/v1"
 	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
 	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
@@ -23,6 +24,7 @@ type Clients struct {
 	User            userv1.UserServiceClient
 	TelephonyAction telephonyv1.TelephonyActionServiceClient
 	B2BUA           sipv1.B2BUAServiceClient
+	Media           mediav1.MediaServiceClient
 }
 
 func NewClients(cfg *config.Config, log zerolog.Logger) (*Clients, error) {
@@ -43,12 +45,18 @@ func NewClients(cfg *config.Config, log zerolog.Logger) (*Clients, error) {
 		return nil, fmt.Errorf("b2bua-service bağlantısı başarısız: %w", err)
 	}
 
+	mediaConn, err := createConnection(cfg, cfg.MediaServiceURL)
+	if err != nil {
+		return nil, fmt.Errorf("media-service bağlantısı başarısız: %w", err)
+	}
+
 	log.Info().Str("event", "GRPC_CLIENTS_READY").Msg("✅ İstemciler ve Trace Interceptor'lar hazır.")
 
 	return &Clients{
 		User:            userv1.NewUserServiceClient(userConn),
 		TelephonyAction: telephonyv1.NewTelephonyActionServiceClient(telephonyConn),
 		B2BUA:           sipv1.NewB2BUAServiceClient(b2buaConn),
+		Media:           mediav1.NewMediaServiceClient(mediaConn),
 	}, nil
 }
 
@@ -4,6 +4,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
@@ -22,20 +23,84 @@ type Config struct {
 	UserServiceURL     string
 	TelephonyActionURL string
 	B2buaServiceURL    string
+	MediaServiceURL    string
 
 	CertPath string
 	KeyPath  string
 	CaPath   string
 
-	AgentMaxConsecutiveFailures int
-	BucketName                  string
+	// PipelineMaxRestarts, TAS pipeline'ının ardışık arızalarda en fazla kaç kez yeniden başlatılacağıdır (en az 1);
+	// ilk başlatma sayılmaz, ardışık arıza sayısı bu değeri aşınca çağrı kapatılır.
+	PipelineMaxRestarts int
+	// MaxCallerFailures, arayan tarafında (sessizlik, anlaşılamayan ifade) ardışık başarısız tur limitidir.
+	MaxCallerFailures     int
+	AgentMinSttConfidence float64
+	BucketName            string
+
+	EventDedupWindowSeconds int
+	// AutoMigrate, açılışta gömülü SQL migration'larının uygulanıp uygulanmayacağıdır.
+	AutoMigrate bool
+
+	DefaultLanguageCode            string
+	Timezone                       string
+	SupportedLanguages             []string
+	CatalogCacheTTLSeconds         int
+	AnnouncementWaitTimeoutSeconds int
+	BridgeRingTimeoutSeconds       int
+	AgentTargetURITemplate         string
+	EchoTestDurationSeconds        int
+	EchoTestMaxLatencyMs           float64
+	QueueDispatchIntervalMs        int
+	PresenceTTLSeconds             int
+	CallbackMaxAttempts            int
+	VoicemailMaxSeconds            int
+	WrapUpSeconds                  int
+	OfferTimeoutSeconds            int
+	OfferMissedStatus              string
+	OfferMaxConsecutiveMisses      int
+	SkillRelaxAfterSeconds         int
+	QueuePriorityStepSeconds       int
+	QueueVIPPriority               int
+	VIPUserTypes                   []string
+	ServiceLevelSeconds            int
+	StatsRefreshSeconds            int
+
+	TranscriptBatchSize       int
+	TranscriptFlushIntervalMs int
 }
 
 func Load() (*Config, error) {
 	_ = godotenv.Load() // Başına alt tire ekle
 
-	maxFailuresStr := getEnvWithDefault("AGENT_MAX_CONSECUTIVE_FAILURES", "3")
-	maxFailures, _ := strconv.Atoi(maxFailuresStr)
+	// Eski tek ayar (AGENT_MAX_CONSECUTIVE_FAILURES) verilmişse iki limitin de varsayılanı olur.
+	legacyMaxFailures := getPositiveIntEnv("AGENT_MAX_CONSECUTIVE_FAILURES", 3)
+	pipelineMaxRestarts := getPositiveIntEnv("AGENT_PIPELINE_MAX_RESTARTS", legacyMaxFailures)
+	maxCallerFailures := getPositiveIntEnv("AGENT_MAX_CALLER_FAILURES", legacyMaxFailures)
+	minConfidence, _ := strconv.ParseFloat(getEnvWithDefault("AGENT_MIN_STT_CONFIDENCE", "0.45"), 64)
+
+	// Pencere sıfır olamaz: TTL'siz idempotency anahtarları Redis'te sınırsız birikir.
+	dedupWindow := getPositiveIntEnv("AGENT_EVENT_DEDUP_WINDOW_SECONDS", 600)
+
+	bridgeRing, _ := strconv.Atoi(getEnvWithDefault("AGENT_BRIDGE_RING_TIMEOUT_SECONDS", "30"))
+	echoDuration, _ := strconv.Atoi(getEnvWithDefault("AGENT_ECHO_TEST_DURATION_SECONDS", "10"))
+	echoMaxLatency, _ := strconv.ParseFloat(getEnvWithDefault("AGENT_ECHO_TEST_MAX_LATENCY_MS", "400"), 64)
+	queueDispatch, _ := strconv.Atoi(getEnvWithDefault("AGENT_QUEUE_DISPATCH_INTERVAL_MS", "1000"))
+	presenceTTL, _ := strconv.Atoi(getEnvWithDefault("AGENT_PRESENCE_TTL_SECONDS", "120"))
+	callbackAttempts, _ := strconv.Atoi(getEnvWithDefault("AGENT_CALLBACK_MAX_ATTEMPTS", "3"))
+	voicemailMax, _ := strconv.Atoi(getEnvWithDefault("AGENT_VOICEMAIL_MAX_SECONDS", "120"))
+	wrapUp, _ := strconv.Atoi(getEnvWithDefault("AGENT_WRAP_UP_SECONDS", "30"))
+	offerTimeout, _ := strconv.Atoi(getEnvWithDefault("AGENT_OFFER_TIMEOUT_SECONDS", "20"))
+	offerMaxMisses, _ := strconv.Atoi(getEnvWithDefault("AGENT_OFFER_MAX_CONSECUTIVE_MISSES", "3"))
+	skillRelax, _ := strconv.Atoi(getEnvWithDefault("AGENT_SKILL_RELAX_AFTER_SECONDS", "30"))
+	priorityStep, _ := strconv.Atoi(getEnvWithDefault("AGENT_QUEUE_PRIORITY_STEP_SECONDS", "60"))
+	vipPriority, _ := strconv.Atoi(getEnvWithDefault("AGENT_QUEUE_VIP_PRIORITY", "5"))
+	serviceLevel, _ := strconv.Atoi(getEnvWithDefault("AGENT_SERVICE_LEVEL_SECONDS", "20"))
+	statsRefresh, _ := strconv.Atoi(getEnvWithDefault("AGENT_STATS_REFRESH_SECONDS", "10"))
+	catalogTTL, _ := strconv.Atoi(getEnvWithDefault("AGENT_CATALOG_CACHE_TTL_SECONDS", "300"))
+	announcementWait, _ := strconv.Atoi(getEnvWithDefault("AGENT_ANNOUNCEMENT_WAIT_TIMEOUT_SECONDS", "30"))
+
+	transcriptBatch, _ := strconv.Atoi(getEnvWithDefault("AGENT_TRANSCRIPT_BATCH_SIZE", "50"))
+	transcriptFlush, _ := strconv.Atoi(getEnvWithDefault("AGENT_TRANSCRIPT_FLUSH_INTERVAL_MS", "500"))
 
 	return &Config{
 		Env:         getEnvWithDefault("ENV", "production"),
@@ -50,13 +115,46 @@ func Load() (*Config, error) {
 		UserServiceURL:     getEnvWithDefault("USER_SERVICE_TARGET_GRPC_URL", "user-service:12011"),
 		TelephonyActionURL: getEnvWithDefault("TELEPHONY_ACTION_TARGET_GRPC_URL", "telephony-action-service:13111"),
 		B2buaServiceURL:    getEnvWithDefault("B2BUA_SERVICE_TARGET_GRPC_URL", "b2bua-service:13081"),
+		MediaServiceURL:    getEnvWithDefault("MEDIA_SERVICE_TARGET_GRPC_URL", "media-service:13031"),
 
 		CertPath: GetEnvOrFail("AGENT_SERVICE_CERT_PATH"),
 		KeyPath:  GetEnvOrFail("AGENT_SERVICE_KEY_PATH"),
 		CaPath:   GetEnvOrFail("GRPC_TLS_CA_PATH"),
 
-		AgentMaxConsecutiveFailures: maxFailures,
-		BucketName:                  getEnvWithDefault("BUCKET_NAME", "sentiric"),
+		PipelineMaxRestarts:   pipelineMaxRestarts,
+		MaxCallerFailures:     maxCallerFailures,
+		AgentMinSttConfidence: minConfidence,
+		BucketName:            getEnvWithDefault("BUCKET_NAME", "sentiric"),
+
+		EventDedupWindowSeconds: dedupWindow,
+		AutoMigrate:             getEnvWithDefault("AGENT_DB_AUTO_MIGRATE", "true") == "true",
+
+		DefaultLanguageCode:            getEnvWithDefault("AGENT_DEFAULT_LANGUAGE_CODE", "tr"),
+		Timezone:                       getEnvWithDefault("AGENT_TIMEZONE", "Europe/Istanbul"),
+		SupportedLanguages:             splitList(getEnvWithDefault("AGENT_SUPPORTED_LANGUAGES", "tr,en")),
+		CatalogCacheTTLSeconds:         catalogTTL,
+		AnnouncementWaitTimeoutSeconds: announcementWait,
+		BridgeRingTimeoutSeconds:       bridgeRing,
+		AgentTargetURITemplate:         getEnvWithDefault("AGENT_TARGET_URI_TEMPLATE", "sip:{agent_id}@agents.sentiric.local"),
+		EchoTestDurationSeconds:        echoDuration,
+		EchoTestMaxLatencyMs:           echoMaxLatency,
+		QueueDispatchIntervalMs:        queueDispatch,
+		PresenceTTLSeconds:             presenceTTL,
+		CallbackMaxAttempts:            callbackAttempts,
+		VoicemailMaxSeconds:            voicemailMax,
+		WrapUpSeconds:                  wrapUp,
+		OfferTimeoutSeconds:            offerTimeout,
+		OfferMissedStatus:              strings.ToUpper(getEnvWithDefault("AGENT_OFFER_MISSED_STATUS", "MISSED")),
+		OfferMaxConsecutiveMisses:      offerMaxMisses,
+		SkillRelaxAfterSeconds:         skillRelax,
+		QueuePriorityStepSeconds:       priorityStep,
+		QueueVIPPriority:               vipPriority,
+		VIPUserTypes:                   splitList(strings.ToLower(getEnvWithDefault("AGENT_VIP_USER_TYPES", "vip"))),
+		ServiceLevelSeconds:            serviceLevel,
+		StatsRefreshSeconds:            statsRefresh,
+
+		TranscriptBatchSize:       transcriptBatch,
+		TranscriptFlushIntervalMs: transcriptFlush,
 	}, nil
 }
 
@@ -75,3 +173,29 @@ func getEnvWithDefault(key, fallback string) string {
 	}
 	return val
 }
+
+// getPositiveIntEnv, pozitif bir tamsayı ayarını okur; değer yoksa, okunamıyorsa veya pozitif değilse
+// uyarı loglayıp varsayılanı döner.
+func getPositiveIntEnv(key string, fallback int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n <= 0 {
+		log.Warn().Str("event", "INVALID_ENV_VAR").Str("variable", key).Str("value", val).Int("fallback", fallback).Msg("Geçersiz ayar, varsayılan kullanılıyor")
+		return fallback
+	}
+	return n
+}
+
+// splitList, virgülle ayrılmış bir ortam değişkenini boş öğeleri atlayarak listeye çevirir.
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
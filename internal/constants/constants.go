@@ -10,16 +10,44 @@ const (
 	StateSpeaking   DialogState = "SPEAKING"
 	StateEnded      DialogState = "ENDED"
 	StateTerminated DialogState = "TERMINATED"
+	// Köprüleme: B-leg çalıyor / iki bacak birbirine bağlı.
+	StateBridging DialogState = "BRIDGING"
+	StateBridged  DialogState = "BRIDGED"
+	// Devir: danışma bacağı açık / çağrı hedefe devredildi.
+	StateTransferring DialogState = "TRANSFERRING"
+	StateTransferred  DialogState = "TRANSFERRED"
+	// Sesli mesaj: karşılama sonrası arayanın mesajı kaydediliyor.
+	StateVoicemail DialogState = "VOICEMAIL"
 )
 
 // EventType, RabbitMQ olay türlerini tanımlar.
 type EventType string
 
 const (
-	EventTypeCallStarted           EventType = "call.started"
-	EventTypeCallEnded             EventType = "call.ended"
-	EventTypeUserIdentifiedForCall EventType = "user.identified.for_call"
-	EventTypeCallTerminateRequest  EventType = "call.terminate.request"
+	EventTypeCallStarted               EventType = "call.started"
+	EventTypeCallEnded                 EventType = "call.ended"
+	EventTypeUserIdentifiedForCall     EventType = "user.identified.for_call"
+	EventTypeCallTerminateRequest      EventType = "call.terminate.request"
+	EventTypeCallRecordingAvailable    EventType = "call.recording.available"
+	EventTypeCallMediaPlaybackFinished EventType = "call.media.playback.finished"
+	EventTypeCallDialogStateChanged    EventType = "agent.call.state.changed"
+
+	// Devir (transfer) komutları ve durum bildirimi.
+	EventTypeTransferRequested    EventType = "agent.transfer.requested"
+	EventTypeTransferComplete     EventType = "agent.transfer.complete"
+	EventTypeTransferCancel       EventType = "agent.transfer.cancel"
+	EventTypeTransferDeclined     EventType = "agent.transfer.declined"
+	EventTypeTransferStateChanged EventType = "agent.transfer.state.changed"
+
+	// Ajan durumu ve kuyruk/geri arama olayları.
+	EventTypeAgentPresenceChanged EventType = "agent.presence.changed"
+	EventTypeQueueEntered         EventType = "agent.queue.entered"
+	EventTypeCallbackRequested    EventType = "agent.callback.requested"
+	EventTypeCallbackStateChanged EventType = "agent.callback.state.changed"
+	EventTypeVoicemailCreated     EventType = "agent.voicemail.created"
+	EventTypeWrapUpStarted        EventType = "agent.wrapup.started"
+	EventTypeWrapUpCompleted      EventType = "agent.wrapup.completed"
+	EventTypeOfferMissed          EventType = "agent.offer.missed"
 )
 
 // AnnouncementID, sistem anonslarını tanımlar.
@@ -33,8 +61,25 @@ const (
 	AnnounceSystemCantHearYou    AnnouncementID = "ANNOUNCE_SYSTEM_CANT_HEAR_YOU"
 	AnnounceSystemCantUnderstand AnnouncementID = "ANNOUNCE_SYSTEM_CANT_UNDERSTAND"
 	AnnounceSystemGoodbye        AnnouncementID = "ANNOUNCE_SYSTEM_GOODBYE"
+	AnnounceCallbackScheduled    AnnouncementID = "ANNOUNCE_CALLBACK_SCHEDULED"
+	AnnounceVoicemailGreeting    AnnouncementID = "ANNOUNCE_VOICEMAIL_GREETING"
+	AnnounceTransferFailed       AnnouncementID = "ANNOUNCE_TRANSFER_FAILED"
 )
 
+// AnnouncementIDs, agent'ın çalabileceği tüm sistem anonslarıdır (başlangıç doğrulamasında kullanılır).
+var AnnouncementIDs = []AnnouncementID{
+	AnnounceGuestWelcome,
+	AnnounceSystemConnecting,
+	AnnounceSystemError,
+	AnnounceSystemMaxFailures,
+	AnnounceSystemCantHearYou,
+	AnnounceSystemCantUnderstand,
+	AnnounceSystemGoodbye,
+	AnnounceCallbackScheduled,
+	AnnounceVoicemailGreeting,
+	AnnounceTransferFailed,
+}
+
 // TemplateID, veritabanındaki prompt şablonlarını tanımlar.
 type TemplateID string
 
@@ -44,3 +89,11 @@ const (
 	PromptSystemRAG        TemplateID = "PROMPT_SYSTEM_RAG"
 	PromptSystemDefault    TemplateID = "PROMPT_SYSTEM_DEFAULT"
 )
+
+// TemplateIDs, agent'ın işleyebileceği tüm prompt şablonlarıdır (başlangıç doğrulamasında kullanılır).
+var TemplateIDs = []TemplateID{
+	PromptWelcomeKnownUser,
+	PromptWelcomeGuest,
+	PromptSystemRAG,
+	PromptSystemDefault,
+}
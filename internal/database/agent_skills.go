@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// GetAgentSkills, verilen ajanların yeteneklerini ajan → yetenek → yetkinlik haritası olarak döner.
+// Yeteneği tanımlı olmayan ajanlar haritada yer almaz.
+func GetAgentSkills(ctx context.Context, db *sql.DB, tenantID string, agentIDs []string) (map[string]map[string]int, error) {
+	out := make(map[string]map[string]int, len(agentIDs))
+	if len(agentIDs) == 0 {
+		return out, nil
+	}
+	rows, err := db.QueryContext(ctx,
+		`SELECT agent_id, skill, proficiency FROM agent_skills WHERE tenant_id = $1 AND agent_id = ANY($2)`,
+		tenantID, agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var agentID, skill string
+		var proficiency int
+		if err := rows.Scan(&agentID, &skill, &proficiency); err != nil {
+			return nil, err
+		}
+		if out[agentID] == nil {
+			out[agentID] = make(map[string]int)
+		}
+		out[agentID][skill] = proficiency
+	}
+	return out, rows.Err()
+}
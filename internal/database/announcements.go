@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// ResolveAnnouncementPath, anonsu şu öncelikle çözer: çağrı dilinde (tam veya temel dil) tenant kaydı,
+// çağrı dilinde sistem kaydı, ardından varsayılan dilde tenant ve sistem kayıtları.
+func ResolveAnnouncementPath(ctx context.Context, db *sql.DB, announcementID, tenantID, languageCode, defaultLanguage string) (string, error) {
+	baseLang, _, _ := strings.Cut(languageCode, "-")
+	var audioPath string
+	query := `SELECT audio_path FROM announcements
+		WHERE id = $1 AND (tenant_id = $2 OR tenant_id = 'system') AND language_code IN ($3, $4, $5)
+		ORDER BY (language_code IN ($3, $4)) DESC, (tenant_id = $2) DESC, (language_code = $3) DESC
+		LIMIT 1`
+	err := db.QueryRowContext(ctx, query, announcementID, tenantID, languageCode, baseLang, defaultLanguage).Scan(&audioPath)
+	return audioPath, err
+}
+
+// GetSystemAnnouncementLanguages, sistem tenant'ındaki anonsları kimlik → tanımlı diller biçiminde döner.
+func GetSystemAnnouncementLanguages(ctx context.Context, db *sql.DB) (map[string]map[string]bool, error) {
+	return systemCatalogLanguages(ctx, db, `SELECT id, language_code FROM announcements WHERE tenant_id = 'system'`)
+}
+
+func systemCatalogLanguages(ctx context.Context, db *sql.DB, query string) (map[string]map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]bool)
+	for rows.Next() {
+		var id, lang string
+		if err := rows.Scan(&id, &lang); err != nil {
+			return nil, err
+		}
+		if result[id] == nil {
+			result[id] = make(map[string]bool)
+		}
+		result[id][lang] = true
+	}
+	return result, rows.Err()
+}
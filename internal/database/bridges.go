@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// BridgeRecord, bir köprü denemesinin konuşmaya yazılan özetidir. Cevaplanmayan köprülerde AnsweredAt sıfırdır.
+type BridgeRecord struct {
+	TargetURI  string
+	Outcome    string
+	AnsweredAt time.Time
+	EndedAt    time.Time
+}
+
+// RecordBridge, köprü sonucunu ve (cevaplandıysa) konuşma süresini çağrının konuşma kaydına yazar.
+func RecordBridge(ctx context.Context, db *sql.DB, callID string, rec BridgeRecord) error {
+	var answeredAt sql.NullTime
+	duration := 0
+	if !rec.AnsweredAt.IsZero() {
+		answeredAt = sql.NullTime{Time: rec.AnsweredAt, Valid: true}
+		duration = int(rec.EndedAt.Sub(rec.AnsweredAt).Round(time.Second).Seconds())
+	}
+
+	query := `UPDATE conversations
+		SET bridge_target = $2, bridge_outcome = $3, bridge_answered_at = $4, bridge_ended_at = $5,
+			bridge_duration_seconds = $6, updated_at = NOW()
+		WHERE call_id = $1`
+	res, err := db.ExecContext(ctx, query, callID, rec.TargetURI, rec.Outcome, answeredAt, rec.EndedAt, duration)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
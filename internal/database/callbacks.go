@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Callback, bir geri arama talebinin kalıcı kaydıdır.
+type Callback struct {
+	ID             string
+	TenantID       string
+	QueueID        string
+	OriginalCallID string
+	CallbackURI    string
+}
+
+func InsertCallback(ctx context.Context, db *sql.DB, cb Callback) error {
+	query := `INSERT INTO callbacks (id, tenant_id, queue_id, original_call_id, callback_uri, status, requested_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, 'PENDING', NOW(), NOW())
+		ON CONFLICT (id) DO NOTHING`
+	_, err := db.ExecContext(ctx, query, cb.ID, cb.TenantID, cb.QueueID, cb.OriginalCallID, cb.CallbackURI)
+	return err
+}
+
+// UpdateCallbackStatus, geri aramanın durumunu günceller; boş agentID/outboundCallID mevcut değeri korur.
+func UpdateCallbackStatus(ctx context.Context, db *sql.DB, id, status string, attempts int, agentID, outboundCallID string) error {
+	query := `UPDATE callbacks
+		SET status = $2, attempts = GREATEST(attempts, $3),
+			agent_id = COALESCE(NULLIF($4, ''), agent_id),
+			outbound_call_id = COALESCE(NULLIF($5, ''), outbound_call_id),
+			updated_at = NOW()
+		WHERE id = $1`
+	_, err := db.ExecContext(ctx, query, id, status, attempts, agentID, outboundCallID)
+	return err
+}
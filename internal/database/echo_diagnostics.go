@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// EchoDiagnostic, bir ECHO_TEST çağrısında ölçülen medya yolu sonuçlarıdır. Gecikmeler trunk RTT değil,
+// media-service'in geri basılan parçalar için verdiği onayın gecikmesidir.
+type EchoDiagnostic struct {
+	CallID           string
+	TenantID         string
+	CallerRtpAddr    string
+	ServerRtpPort    uint32
+	Samples          int
+	MediaAckMs       float64
+	MediaAckJitterMs float64
+	MinMediaAckMs    float64
+	MaxMediaAckMs    float64
+	Success          bool
+	FailureReason    string
+	StartedAt        time.Time
+	FinishedAt       time.Time
+}
+
+// InsertEchoDiagnostic, echo testi sonucunu echo_test_diagnostics tablosuna yazar.
+// Hiç örnek alınamadıysa ölçüm kolonları NULL bırakılır.
+func InsertEchoDiagnostic(ctx context.Context, db *sql.DB, d EchoDiagnostic) error {
+	measured := func(v float64) sql.NullFloat64 {
+		return sql.NullFloat64{Float64: v, Valid: d.Samples > 0}
+	}
+	query := `INSERT INTO echo_test_diagnostics
+		(call_id, tenant_id, caller_rtp_addr, server_rtp_port, samples, media_ack_ms, media_ack_jitter_ms, min_media_ack_ms, max_media_ack_ms,
+		 success, failure_reason, started_at, finished_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13)`
+	_, err := db.ExecContext(ctx, query,
+		d.CallID, d.TenantID, d.CallerRtpAddr, int64(d.ServerRtpPort), d.Samples,
+		measured(d.MediaAckMs), measured(d.MediaAckJitterMs), measured(d.MinMediaAckMs), measured(d.MaxMediaAckMs),
+		d.Success, d.FailureReason, d.StartedAt, d.FinishedAt)
+	return err
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// migrationLockID, aynı anda açılan replikalardan yalnızca birinin migration uygulaması için kullanılan advisory lock anahtarıdır.
+const migrationLockID = 7_231_031_001
+
+// Migrate, files içindeki henüz uygulanmamış .sql dosyalarını ad sırasıyla, her biri kendi transaction'ında uygular.
+// Uygulanan sürümler schema_migrations tablosuna yazılır; replikalar advisory lock ile sıraya girer.
+func Migrate(ctx context.Context, db *sql.DB, files fs.FS, log zerolog.Logger) error {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("migration kilidi alınamadı: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT        PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`); err != nil {
+		return err
+	}
+
+	applied := make(map[string]bool)
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if applied[version] {
+			continue
+		}
+		body, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		if err := applyMigration(ctx, conn, version, string(body)); err != nil {
+			return fmt.Errorf("%s uygulanamadı: %w", name, err)
+		}
+		log.Info().Str("event", "MIGRATION_APPLIED").Str("version", version).Msg("🗄️ Veritabanı migration'ı uygulandı.")
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, version, body string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// externalTables, başka servislere ait olup migration'ların koşullu olarak dokunduğu tablolardır. Eksikse ilgili
+// kolonlar, yabancı anahtarlar ve tetikleyiciler atlanmıştır.
+var externalTables = []string{"conversations", "announcements", "templates"}
+
+// MigrateWhenReady, veritabanı erişilebilir olana kadar bekleyip migration'ları uygular. Hata durumunda her
+// denemeyi failures ile sayar ve retryInterval sonra yeniden dener; ctx iptal edilirse ctx hatasını döner.
+func MigrateWhenReady(ctx context.Context, db *sql.DB, files fs.FS, retryInterval time.Duration, failures prometheus.Counter, log zerolog.Logger) error {
+	for {
+		opCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		err := Migrate(opCtx, db, files, log)
+		cancel()
+		if err == nil {
+			log.Info().Str("event", "MIGRATIONS_UP_TO_DATE").Msg("✅ Veritabanı şeması güncel.")
+			warnMissingExternalTables(ctx, db, log)
+			return nil
+		}
+		failures.Inc()
+		log.Warn().Str("event", "MIGRATION_FAILED").Err(err).Msg("Migration'lar uygulanamadı, tekrar denenecek.")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+func warnMissingExternalTables(ctx context.Context, db *sql.DB, log zerolog.Logger) {
+	for _, table := range externalTables {
+		var exists bool
+		if err := db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists); err != nil || exists {
+			continue
+		}
+		log.Warn().Str("event", "MIGRATION_EXTERNAL_TABLE_MISSING").Str("table", table).Msg("⚠️ Başka servise ait tablo yok; ona bağlı şema adımları atlandı.")
+	}
+}
@@ -61,20 +61,6 @@ func ConnectRedis(ctx context.Context, url string, log zerolog.Logger) *redis.Cl
 	return rdb
 }
 
-func GetAnnouncementPathFromDB(db *sql.DB, announcementID, tenantID, languageCode string) (string, error) {
-	var audioPath string
-	query := `SELECT audio_path FROM announcements WHERE id = $1 AND language_code = $2 AND (tenant_id = $3 OR tenant_id = 'system') ORDER BY tenant_id DESC LIMIT 1`
-	err := db.QueryRow(query, announcementID, languageCode, tenantID).Scan(&audioPath)
-	return audioPath, err
-}
-
-func GetTemplateFromDB(db *sql.DB, templateID, languageCode, tenantID string) (string, error) {
-	var content string
-	query := `SELECT content FROM templates WHERE id = $1 AND language_code = $2 AND (tenant_id = $3 OR tenant_id = 'system') ORDER BY tenant_id DESC LIMIT 1`
-	err := db.QueryRow(query, templateID, languageCode, tenantID).Scan(&content)
-	return content, err
-}
-
 func CreateConversation(db *sql.DB, callID, tenantID string, channel string) error {
 	query := `INSERT INTO conversations (call_id, tenant_id, channel, status, created_at) VALUES ($1, $2, $3, 'ACTIVE', NOW()) ON CONFLICT (id) DO NOTHING`
 	_, err := db.Exec(query, callID, tenantID, channel)
@@ -86,14 +72,3 @@ func UpdateConversationStatus(db *sql.DB, callID, status string) error {
 	_, err := db.Exec(query, status, callID)
 	return err
 }
-
-func AddTranscript(db *sql.DB, callID, senderType, message string) error {
-	var convID string
-	err := db.QueryRow("SELECT id FROM conversations WHERE call_id = $1 ORDER BY created_at DESC LIMIT 1", callID).Scan(&convID)
-	if err != nil {
-		return err
-	}
-	query := `INSERT INTO transcripts (conversation_id, sender_type, message_text, created_at) VALUES ($1, $2, $3, NOW())`
-	_, err = db.Exec(query, convID, senderType, message)
-	return err
-}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// GetOfferTimeoutSeconds, kuyruğun teklif cevap süresini döner; kuyruğa özel ayar yoksa ok=false döner.
+func GetOfferTimeoutSeconds(ctx context.Context, db *sql.DB, tenantID, queueID string) (int, bool, error) {
+	var seconds sql.NullInt32
+	err := db.QueryRowContext(ctx, `SELECT offer_timeout_seconds FROM queue_settings WHERE tenant_id = $1 AND queue_id = $2`, tenantID, queueID).Scan(&seconds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return int(seconds.Int32), seconds.Valid, nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// QueueInterval, bir kuyruğun tek bir rapor aralığındaki toplamlarıdır.
+type QueueInterval struct {
+	TenantID           string
+	QueueID            string
+	IntervalStart      time.Time
+	Offered            int64
+	Answered           int64
+	Abandoned          int64
+	AnsweredWithinSL   int64
+	AnswerWaitMsTotal  int64
+	AbandonWaitMsTotal int64
+	TimedOut           int64
+	Callbacks          int64
+	Voicemails         int64
+}
+
+// UpsertQueueInterval, aralık özetini yazar. Aynı aralık tekrar yazılırsa (ör. iki replika) son değerler geçerlidir.
+func UpsertQueueInterval(ctx context.Context, db *sql.DB, r QueueInterval) error {
+	query := `INSERT INTO queue_interval_stats
+		(tenant_id, queue_id, interval_start, offered, answered, abandoned, answered_within_sl, answer_wait_ms_total, abandon_wait_ms_total,
+			timed_out, callbacks, voicemails)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (tenant_id, queue_id, interval_start) DO UPDATE SET
+			offered = EXCLUDED.offered,
+			answered = EXCLUDED.answered,
+			abandoned = EXCLUDED.abandoned,
+			answered_within_sl = EXCLUDED.answered_within_sl,
+			answer_wait_ms_total = EXCLUDED.answer_wait_ms_total,
+			abandon_wait_ms_total = EXCLUDED.abandon_wait_ms_total,
+			timed_out = EXCLUDED.timed_out,
+			callbacks = EXCLUDED.callbacks,
+			voicemails = EXCLUDED.voicemails`
+	_, err := db.ExecContext(ctx, query, r.TenantID, r.QueueID, r.IntervalStart,
+		r.Offered, r.Answered, r.Abandoned, r.AnsweredWithinSL, r.AnswerWaitMsTotal, r.AbandonWaitMsTotal,
+		r.TimedOut, r.Callbacks, r.Voicemails)
+	return err
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrConversationNotFound, çağrıya ait bir konuşma kaydı bulunamadığında döner.
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// Recording, bir konuşmaya bağlı ses kaydını temsil eder.
+type Recording struct {
+	Bucket       string
+	RecordingURI string
+	PublicURL    string
+	CreatedAt    time.Time
+}
+
+// NormalizeRecordingURI, şemasız bir nesne yolunu verilen bucket altında s3:// URI'sine çevirir
+// ve kaydın fiilen bulunduğu bucket'ı döner.
+func NormalizeRecordingURI(bucket, uri string) (string, string) {
+	if rest, ok := strings.CutPrefix(uri, "s3://"); ok {
+		if b, _, found := strings.Cut(rest, "/"); found && b != "" {
+			return b, uri
+		}
+		return bucket, uri
+	}
+	if strings.Contains(uri, "://") {
+		return bucket, uri
+	}
+	return bucket, "s3://" + bucket + "/" + strings.TrimPrefix(uri, "/")
+}
+
+// AddRecording, kaydı çağrının en güncel konuşmasına bağlar. Aynı URI tekrar gelirse yoksayılır.
+func AddRecording(ctx context.Context, db *sql.DB, callID string, rec Recording) error {
+	query := `INSERT INTO recordings (conversation_id, call_id, bucket, recording_uri, public_url, created_at)
+		SELECT id, $1, $2, $3, NULLIF($4, ''), NOW() FROM conversations WHERE call_id = $1 ORDER BY created_at DESC LIMIT 1
+		ON CONFLICT (conversation_id, recording_uri) DO NOTHING`
+	res, err := db.ExecContext(ctx, query, callID, rec.Bucket, rec.RecordingURI, rec.PublicURL)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM conversations WHERE call_id = $1)", callID).Scan(&exists); err == nil && !exists {
+			return ErrConversationNotFound
+		}
+	}
+	return nil
+}
+
+// GetRecordings, çağrının konuşmalarına ait kayıtları oluşturulma sırasıyla döner.
+// Sonuçlar her zaman verilen tenant ile sınırlandırılır.
+func GetRecordings(ctx context.Context, db *sql.DB, callID, tenantID string) ([]Recording, error) {
+	query := `SELECT r.bucket, r.recording_uri, COALESCE(r.public_url, ''), r.created_at
+		FROM recordings r JOIN conversations c ON c.id = r.conversation_id
+		WHERE c.call_id = $1 AND c.tenant_id = $2
+		ORDER BY r.created_at`
+	rows, err := db.QueryContext(ctx, query, callID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recordings []Recording
+	for rows.Next() {
+		var r Recording
+		if err := rows.Scan(&r.Bucket, &r.RecordingURI, &r.PublicURL, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		recordings = append(recordings, r)
+	}
+	return recordings, rows.Err()
+}
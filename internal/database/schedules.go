@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// BusinessSchedule, bir tenant'ın çalışma takviminin ayarlarıdır.
+type BusinessSchedule struct {
+	TenantID                 string
+	ID                       string
+	Timezone                 string
+	OutOfHoursAction         string
+	OutOfHoursAnnouncementID string
+}
+
+// TimeRange, "HH:MM" biçiminde bir açılış-kapanış aralığıdır.
+type TimeRange struct {
+	Open  string
+	Close string
+}
+
+// Holiday, takvimdeki bir tatil günüdür. Hours nil ise gün tamamen kapalıdır.
+type Holiday struct {
+	Name  string
+	Hours *TimeRange
+}
+
+// GetBusinessSchedule, takvimi tenant'a özel kayıttan çözer; kayıt yoksa nil döner.
+func GetBusinessSchedule(ctx context.Context, db *sql.DB, tenantID, scheduleID string) (*BusinessSchedule, error) {
+	s := BusinessSchedule{TenantID: tenantID, ID: scheduleID}
+	var announcementID sql.NullString
+	query := `SELECT timezone, out_of_hours_action, out_of_hours_announcement_id
+		FROM business_schedules WHERE tenant_id = $1 AND id = $2`
+	err := db.QueryRowContext(ctx, query, tenantID, scheduleID).Scan(&s.Timezone, &s.OutOfHoursAction, &announcementID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.OutOfHoursAnnouncementID = announcementID.String
+	return &s, nil
+}
+
+// GetBusinessHours, takvimin verilen gündeki (0 = Pazar) mesai aralıklarını döner.
+func GetBusinessHours(ctx context.Context, db *sql.DB, tenantID, scheduleID string, dayOfWeek int) ([]TimeRange, error) {
+	query := `SELECT to_char(open_time, 'HH24:MI'), to_char(close_time, 'HH24:MI')
+		FROM business_hours WHERE tenant_id = $1 AND schedule_id = $2 AND day_of_week = $3
+		ORDER BY open_time`
+	rows, err := db.QueryContext(ctx, query, tenantID, scheduleID, dayOfWeek)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranges []TimeRange
+	for rows.Next() {
+		var r TimeRange
+		if err := rows.Scan(&r.Open, &r.Close); err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, rows.Err()
+}
+
+// GetHoliday, verilen tarih ("YYYY-MM-DD") takvimde tatilse kaydı döner; değilse nil döner.
+func GetHoliday(ctx context.Context, db *sql.DB, tenantID, scheduleID, date string) (*Holiday, error) {
+	var (
+		name        sql.NullString
+		open, close sql.NullString
+	)
+	query := `SELECT name, to_char(open_time, 'HH24:MI'), to_char(close_time, 'HH24:MI')
+		FROM holidays WHERE tenant_id = $1 AND schedule_id = $2 AND holiday_date = $3::date`
+	err := db.QueryRowContext(ctx, query, tenantID, scheduleID, date).Scan(&name, &open, &close)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	h := &Holiday{Name: name.String}
+	if open.Valid && close.Valid {
+		h.Hours = &TimeRange{Open: open.String, Close: close.String}
+	}
+	return h, nil
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// ResolveTemplateContent, prompt şablonunu anonslarla aynı öncelikle çözer: çağrı dilinde (tam veya temel dil)
+// tenant kaydı, çağrı dilinde sistem kaydı, ardından varsayılan dilde tenant ve sistem kayıtları.
+func ResolveTemplateContent(ctx context.Context, db *sql.DB, templateID, tenantID, languageCode, defaultLanguage string) (string, error) {
+	baseLang, _, _ := strings.Cut(languageCode, "-")
+	var content string
+	query := `SELECT content FROM templates
+		WHERE id = $1 AND (tenant_id = $2 OR tenant_id = 'system') AND language_code IN ($3, $4, $5)
+		ORDER BY (language_code IN ($3, $4)) DESC, (tenant_id = $2) DESC, (language_code = $3) DESC
+		LIMIT 1`
+	err := db.QueryRowContext(ctx, query, templateID, tenantID, languageCode, baseLang, defaultLanguage).Scan(&content)
+	return content, err
+}
+
+// GetSystemTemplateLanguages, sistem tenant'ındaki şablonları kimlik → tanımlı diller biçiminde döner.
+func GetSystemTemplateLanguages(ctx context.Context, db *sql.DB) (map[string]map[string]bool, error) {
+	return systemCatalogLanguages(ctx, db, `SELECT id, language_code FROM templates WHERE tenant_id = 'system'`)
+}
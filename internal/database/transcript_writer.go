@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+const (
+	transcriptShards       = 4
+	transcriptShardBuffer  = 1000
+	transcriptFlushRetries = 3
+)
+
+// TranscriptEntry, yazılmayı bekleyen tek bir ifadedir. CreatedAt yakalama anıdır;
+// böylece yazım gecikse bile sıralama korunur.
+type TranscriptEntry struct {
+	CallID      string
+	SenderType  string
+	MessageText string
+	CreatedAt   time.Time
+}
+
+// TranscriptWriter, ifadeleri toplu ve asenkron olarak transcripts tablosuna yazar.
+// Aynı çağrının ifadeleri her zaman aynı shard'a düşer; bu da konuşma içi sıralamayı garanti eder.
+// Enqueue hiç beklemez: shard tamponu doluysa (veritabanı yavaşsa) ifade düşürülür ve dropped ile sayılır; böylece
+// pipeline akışını okuyan döngü veritabanı yüzünden durmaz.
+type TranscriptWriter struct {
+	db            *sql.DB
+	log           zerolog.Logger
+	dropped       prometheus.Counter
+	shards        []chan TranscriptEntry
+	batchSize     int
+	flushInterval time.Duration
+}
+
+func NewTranscriptWriter(db *sql.DB, log zerolog.Logger, batchSize int, flushInterval time.Duration, dropped prometheus.Counter) *TranscriptWriter {
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	if flushInterval <= 0 {
+		flushInterval = 500 * time.Millisecond
+	}
+	w := &TranscriptWriter{
+		db:            db,
+		log:           log,
+		dropped:       dropped,
+		shards:        make([]chan TranscriptEntry, transcriptShards),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+	}
+	for i := range w.shards {
+		w.shards[i] = make(chan TranscriptEntry, transcriptShardBuffer)
+	}
+	return w
+}
+
+// Start, shard işçilerini başlatır. ctx iptal edildiğinde tamponlar boşaltılır ve işçiler wg'yi serbest bırakır.
+func (w *TranscriptWriter) Start(ctx context.Context, wg *sync.WaitGroup) {
+	for i := range w.shards {
+		wg.Add(1)
+		go func(ch chan TranscriptEntry) {
+			defer wg.Done()
+			w.run(ctx, ch)
+		}(w.shards[i])
+	}
+}
+
+// Enqueue, ifadeyi bloklamadan çağrının shard'ına ekler. Tampon doluysa ifade düşürülür ve false döner.
+func (w *TranscriptWriter) Enqueue(e TranscriptEntry) bool {
+	select {
+	case w.shardFor(e.CallID) <- e:
+		return true
+	default:
+	}
+
+	w.dropped.Inc()
+	w.log.Warn().Str("event", "TRANSCRIPT_DROPPED").Str("call_id", e.CallID).Msg("Transcript tamponu dolu, ifade düşürüldü.")
+	return false
+}
+
+func (w *TranscriptWriter) shardFor(callID string) chan TranscriptEntry {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(callID))
+	return w.shards[h.Sum32()%uint32(len(w.shards))]
+}
+
+func (w *TranscriptWriter) run(ctx context.Context, ch chan TranscriptEntry) {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]TranscriptEntry, 0, w.batchSize)
+	for {
+		select {
+		case e := <-ch:
+			batch = append(batch, e)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-ch:
+					batch = append(batch, e)
+				default:
+					if len(batch) > 0 {
+						w.flush(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush, batch'i tek transaction içinde yazar; geçici hatalarda artan beklemeyle tekrar dener.
+// İşçi bu sürede bloklandığı için shard tamponu dolar ve backpressure üreticiye yansır.
+func (w *TranscriptWriter) flush(batch []TranscriptEntry) {
+	var err error
+	for attempt := 1; attempt <= transcriptFlushRetries; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = w.writeBatch(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+		w.log.Warn().Str("event", "TRANSCRIPT_FLUSH_RETRY").Int("attempt", attempt).Int("size", len(batch)).Err(err).Msg("Transcript batch yazılamadı, tekrar denenecek.")
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+
+	w.dropped.Add(float64(len(batch)))
+	w.log.Error().Str("event", "TRANSCRIPT_FLUSH_FAILED").Int("size", len(batch)).Err(err).Msg("Transcript batch kalıcı olarak yazılamadı.")
+}
+
+func (w *TranscriptWriter) writeBatch(ctx context.Context, batch []TranscriptEntry) error {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	convIDs := make(map[string]string)
+	var (
+		placeholders []string
+		args         []interface{}
+	)
+	for _, e := range batch {
+		convID, ok := convIDs[e.CallID]
+		if !ok {
+			err := tx.QueryRowContext(ctx, "SELECT id FROM conversations WHERE call_id = $1 ORDER BY created_at DESC LIMIT 1", e.CallID).Scan(&convID)
+			if err == sql.ErrNoRows {
+				w.log.Warn().Str("event", "TRANSCRIPT_CONVERSATION_NOT_FOUND").Str("call_id", e.CallID).Msg("Konuşma bulunamadı, ifade atlanıyor.")
+				convIDs[e.CallID] = ""
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			convIDs[e.CallID] = convID
+		}
+		if convID == "" {
+			continue
+		}
+
+		n := len(args)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4))
+		args = append(args, convID, e.SenderType, e.MessageText, e.CreatedAt)
+	}
+
+	if len(placeholders) == 0 {
+		return tx.Commit()
+	}
+
+	query := "INSERT INTO transcripts (conversation_id, sender_type, message_text, created_at) VALUES " + strings.Join(placeholders, ", ")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
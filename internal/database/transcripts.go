@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TranscriptLine, bir konuşmadaki tek bir ifadeyi temsil eder.
+type TranscriptLine struct {
+	SenderType  string
+	MessageText string
+	CreatedAt   time.Time
+}
+
+// GetTranscript, çağrının konuşmalarına ait ifadeleri kronolojik sırayla döner.
+// Sonuçlar her zaman verilen tenant ile sınırlandırılır.
+func GetTranscript(ctx context.Context, db *sql.DB, callID, tenantID string) ([]TranscriptLine, error) {
+	query := `SELECT t.sender_type, t.message_text, t.created_at
+		FROM transcripts t JOIN conversations c ON c.id = t.conversation_id
+		WHERE c.call_id = $1 AND c.tenant_id = $2
+		ORDER BY t.created_at`
+	rows, err := db.QueryContext(ctx, query, callID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []TranscriptLine
+	for rows.Next() {
+		var t TranscriptLine
+		if err := rows.Scan(&t.SenderType, &t.MessageText, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		lines = append(lines, t)
+	}
+	return lines, rows.Err()
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// VoiceModelSettings, bir tenant/dil kombinasyonu için tanımlı STT/TTS modelleridir.
+type VoiceModelSettings struct {
+	TenantID           string
+	LanguageCode       string
+	SttModelID         string
+	TtsModelID         string
+	FallbackSttModelID string
+	FallbackTtsModelID string
+}
+
+// GetVoiceModelSettings, tenant ve sistem ayarlarını en özelden en genele doğru sıralı döner:
+// önce tenant, sonra 'system'; her biri içinde tam dil, temel dil (tr-TR -> tr), sonra '*'.
+func GetVoiceModelSettings(ctx context.Context, db *sql.DB, tenantID, languageCode string) ([]VoiceModelSettings, error) {
+	baseLang, _, _ := strings.Cut(languageCode, "-")
+	query := `SELECT tenant_id, language_code, COALESCE(stt_model_id, ''), COALESCE(tts_model_id, ''),
+			COALESCE(fallback_stt_model_id, ''), COALESCE(fallback_tts_model_id, '')
+		FROM tenant_voice_models
+		WHERE (tenant_id = $1 OR tenant_id = 'system') AND language_code IN ($2, $3, '*')
+		ORDER BY (tenant_id = $1) DESC,
+			CASE language_code WHEN $2 THEN 0 WHEN $3 THEN 1 ELSE 2 END`
+	rows, err := db.QueryContext(ctx, query, tenantID, languageCode, baseLang)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []VoiceModelSettings
+	for rows.Next() {
+		var v VoiceModelSettings
+		if err := rows.Scan(&v.TenantID, &v.LanguageCode, &v.SttModelID, &v.TtsModelID, &v.FallbackSttModelID, &v.FallbackTtsModelID); err != nil {
+			return nil, err
+		}
+		settings = append(settings, v)
+	}
+	return settings, rows.Err()
+}
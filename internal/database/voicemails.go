@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Voicemail, bir çağrıda bırakılan sesli mesajdır.
+type Voicemail struct {
+	ID           int64
+	CallID       string
+	TenantID     string
+	QueueID      string
+	CallerURI    string
+	Bucket       string
+	RecordingURI string
+	PublicURL    string
+	CreatedAt    time.Time
+}
+
+// StartVoicemail, kayıt başlarken çağrının en güncel konuşmasına bağlı RECORDING durumunda bir sesli mesaj açar.
+// Kaydın yazılacağı URI satıra baştan yazılır; gelen kayıt yalnızca bu URI ile eşleşirse bağlanır.
+func StartVoicemail(ctx context.Context, db *sql.DB, v Voicemail) error {
+	query := `INSERT INTO voicemails (conversation_id, call_id, tenant_id, queue_id, caller_uri, status, bucket, recording_uri, created_at)
+		SELECT id, $1, $2, NULLIF($3, ''), NULLIF($4, ''), 'RECORDING', $5, $6, NOW() FROM conversations WHERE call_id = $1 ORDER BY created_at DESC LIMIT 1`
+	res, err := db.ExecContext(ctx, query, v.CallID, v.TenantID, v.QueueID, v.CallerURI, v.Bucket, v.RecordingURI)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
+
+// AttachVoicemailRecording, çağrıda kaydı bekleyen ve URI'si gelen kayıtla aynı olan sesli mesaja kaydı bağlar ve
+// mesajı NEW yapar. Çağrının başka kayıtları (ör. pipeline oturum kaydı) sesli mesaja bağlanmaz; eşleşen bekleyen
+// bir sesli mesaj yoksa nil döner.
+func AttachVoicemailRecording(ctx context.Context, db *sql.DB, callID string, rec Recording) (*Voicemail, error) {
+	query := `UPDATE voicemails SET status = 'NEW', bucket = $2, recording_uri = $3, public_url = NULLIF($4, ''), recorded_at = NOW()
+		WHERE id = (SELECT id FROM voicemails WHERE call_id = $1 AND status = 'RECORDING' AND recording_uri = $3 ORDER BY created_at DESC LIMIT 1)
+		RETURNING id, call_id, tenant_id, COALESCE(queue_id, ''), COALESCE(caller_uri, ''), created_at`
+	v := Voicemail{Bucket: rec.Bucket, RecordingURI: rec.RecordingURI, PublicURL: rec.PublicURL}
+	err := db.QueryRowContext(ctx, query, callID, rec.Bucket, rec.RecordingURI, rec.PublicURL).
+		Scan(&v.ID, &v.CallID, &v.TenantID, &v.QueueID, &v.CallerURI, &v.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrUnknownDisposition, tenant'ın tanımlı sonuç kodları arasında olmayan bir kod gönderildiğinde döner.
+var ErrUnknownDisposition = errors.New("unknown disposition code")
+
+// ErrNotHandlingAgent, sonucu gönderen ajan görüşmeyi yürüten ajan değilse döner.
+var ErrNotHandlingAgent = errors.New("agent did not handle the call")
+
+// Disposition, ajanın görüşme sonrası girdiği sonuç kodu ve notudur.
+type Disposition struct {
+	AgentID string
+	Code    string
+	Notes   string
+}
+
+// GetWrapUpSeconds, tenant'ın wrap-up süresini döner; tenant'a özel ayar yoksa ok=false döner.
+func GetWrapUpSeconds(ctx context.Context, db *sql.DB, tenantID string) (int, bool, error) {
+	var seconds int
+	err := db.QueryRowContext(ctx, `SELECT duration_seconds FROM tenant_wrap_up_settings WHERE tenant_id = $1`, tenantID).Scan(&seconds)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return seconds, true, nil
+}
+
+// ValidateDisposition, kodun tenant için geçerli olduğunu doğrular. Hiç kod tanımlamamış tenant'larda her kod geçerlidir.
+func ValidateDisposition(ctx context.Context, db *sql.DB, tenantID, code string) error {
+	query := `SELECT EXISTS (SELECT 1 FROM disposition_codes WHERE tenant_id = $1 AND code = $2 AND active),
+		EXISTS (SELECT 1 FROM disposition_codes WHERE tenant_id = $1)`
+	var known, hasCodes bool
+	if err := db.QueryRowContext(ctx, query, tenantID, code).Scan(&known, &hasCodes); err != nil {
+		return err
+	}
+	if hasCodes && !known {
+		return ErrUnknownDisposition
+	}
+	return nil
+}
+
+// AssignConversationAgent, çağrıyı yürüten ajanı (devredilen veya giden aramayı yapan) konuşmaya yazar; sonuç
+// yalnızca bu ajandan kabul edilir (bkz. SaveDisposition).
+func AssignConversationAgent(ctx context.Context, db *sql.DB, callID, tenantID, agentID string) error {
+	query := `UPDATE conversations SET agent_id = $3, updated_at = NOW() WHERE call_id = $1 AND tenant_id = $2`
+	_, err := db.ExecContext(ctx, query, callID, tenantID, agentID)
+	return err
+}
+
+// SaveDisposition, sonucu çağrının konuşmasına yazar. Konuşma tenant'a ait değilse ErrConversationNotFound,
+// gönderen ajan çağrıyı yürüten ajan değilse ErrNotHandlingAgent döner.
+func SaveDisposition(ctx context.Context, db *sql.DB, callID, tenantID string, d Disposition) error {
+	query := `UPDATE conversations
+		SET disposition_code = $4, disposition_notes = NULLIF($5, ''), wrapped_up_at = NOW(), updated_at = NOW()
+		WHERE call_id = $1 AND tenant_id = $2 AND agent_id = $3`
+	res, err := db.ExecContext(ctx, query, callID, tenantID, d.AgentID, d.Code, d.Notes)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	var exists bool
+	err = db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM conversations WHERE call_id = $1 AND tenant_id = $2)`, callID, tenantID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrConversationNotFound
+	}
+	return ErrNotHandlingAgent
+}
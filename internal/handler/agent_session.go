@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	workspacev1 "github.com/sentiric/sentiric-agent-service/gen/go/sentiric/agent/v1"
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/presence"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// agentIdentity, ajan API isteğini yapan ajandır.
+type agentIdentity struct {
+	TenantID string
+	AgentID  string
+}
+
+// authorizeAgent, ajan API isteğinin kimliğini döner. Kimlik istek gövdesinden değil, mTLS ile bağlanan Web Agent
+// geçidinin kimliği doğruladıktan sonra eklediği x-tenant-id ve x-agent-id metadata'sından okunur; biri eksikse
+// istek reddedilir.
+func authorizeAgent(ctx context.Context) (agentIdentity, error) {
+	// [ARCH-COMPLIANCE] Tenant Isolation: tenant ve ajan yalnızca doğrulanmış metadata'dan gelir.
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return agentIdentity{}, err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	vals := md.Get("x-agent-id")
+	if len(vals) == 0 || vals[0] == "" {
+		return agentIdentity{}, status.Error(codes.Unauthenticated, "x-agent-id metadata zorunludur")
+	}
+	return agentIdentity{TenantID: tenantID, AgentID: vals[0]}, nil
+}
+
+// Login, ajan masaüstü oturumunu açar. Oturumu olan ajanlara çağrılar doğrudan devredilmez, StreamOffers ile teklif edilir.
+func (h *CallHandler) Login(ctx context.Context, req *workspacev1.LoginRequest) (*workspacev1.LoginResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st := strings.ToUpper(req.Status)
+	if st == "" {
+		st = presence.StatusOnline
+	}
+	if st != presence.StatusOnline && st != presence.StatusBreak {
+		return nil, status.Error(codes.InvalidArgument, "başlangıç durumu ONLINE veya BREAK olmalıdır")
+	}
+
+	sessionID := newID("sess-")
+	if err := h.presence.StartSession(ctx, agent.TenantID, agent.AgentID, sessionID, st); err != nil {
+		h.log.Error().Str("event", "AGENT_LOGIN_FAILED").Str("agent_id", agent.AgentID).Err(err).Msg("Ajan oturumu açılamadı.")
+		return nil, status.Error(codes.Internal, "oturum açılamadı")
+	}
+	h.log.Info().Str("event", "AGENT_LOGIN").Str("agent_id", agent.AgentID).Str("tenant_id", agent.TenantID).Str("status", st).Msg("👤 Ajan oturum açtı.")
+	return &workspacev1.LoginResponse{SessionId: sessionID, Status: st}, nil
+}
+
+// Logout, ajanı OFFLINE yapar; süren görüşmesi etkilenmez.
+func (h *CallHandler) Logout(ctx context.Context, _ *workspacev1.LogoutRequest) (*workspacev1.LogoutResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.presence.SetStatus(ctx, agent.TenantID, agent.AgentID, presence.StatusOffline); err != nil {
+		return nil, status.Error(codes.Internal, "oturum kapatılamadı")
+	}
+	h.log.Info().Str("event", "AGENT_LOGOUT").Str("agent_id", agent.AgentID).Str("tenant_id", agent.TenantID).Msg("👋 Ajan oturumu kapattı.")
+	return &workspacev1.LogoutResponse{}, nil
+}
+
+// SetPresence, ajanın ONLINE/BREAK geçişlerini yapar. BUSY ve WRAP_UP agent tarafından yönetilir;
+// wrap-up'tan çıkmak için EndWrapUp kullanılır. Görüşmedeki ajan BREAK seçerse görüşme bitince molaya geçer.
+func (h *CallHandler) SetPresence(ctx context.Context, req *workspacev1.SetPresenceRequest) (*workspacev1.PresenceResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st := strings.ToUpper(req.Status)
+	if st != presence.StatusOnline && st != presence.StatusBreak {
+		return nil, status.Error(codes.InvalidArgument, "durum ONLINE veya BREAK olmalıdır")
+	}
+
+	cur, err := h.presence.Get(ctx, agent.TenantID, agent.AgentID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ajan durumu okunamadı")
+	}
+	if cur.Status == presence.StatusOffline {
+		return nil, status.Error(codes.FailedPrecondition, "ajan oturumu açık değil")
+	}
+	if st == presence.StatusOnline && (cur.Status == presence.StatusBusy || cur.Status == presence.StatusWrapUp) {
+		return nil, status.Errorf(codes.FailedPrecondition, "ajan %s durumunda", cur.Status)
+	}
+	if err := h.presence.SetStatus(ctx, agent.TenantID, agent.AgentID, st); err != nil {
+		return nil, status.Error(codes.Internal, "ajan durumu yazılamadı")
+	}
+	h.log.Info().Str("event", "AGENT_PRESENCE_SET").Str("agent_id", agent.AgentID).Str("status", st).Msg("Ajan durumu değişti.")
+	return &workspacev1.PresenceResponse{Status: st, Since: timestamppb.Now()}, nil
+}
+
+// EndWrapUp, ajanın wrap-up'ını süre dolmadan bitirip ONLINE yapar.
+func (h *CallHandler) EndWrapUp(ctx context.Context, _ *workspacev1.EndWrapUpRequest) (*workspacev1.PresenceResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ended, err := h.presence.EndWrapUp(ctx, agent.TenantID, agent.AgentID, "")
+	if err != nil {
+		return nil, status.Error(codes.Internal, "wrap-up sonlandırılamadı")
+	}
+	if !ended {
+		return nil, status.Error(codes.FailedPrecondition, "ajan wrap-up durumunda değil")
+	}
+	return &workspacev1.PresenceResponse{Status: presence.StatusOnline, Since: timestamppb.Now()}, nil
+}
+
+// StreamOffers, oturum açık kaldığı sürece ajana teklifleri iletir ve ajan durumunun süresini uzatır.
+// Akış kapanırsa durum kendiliğinden düşer (bkz. AGENT_PRESENCE_TTL_SECONDS).
+func (h *CallHandler) StreamOffers(req *workspacev1.StreamOffersRequest, stream grpc.ServerStreamingServer[workspacev1.QueueOffer]) error {
+	ctx := stream.Context()
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return err
+	}
+	l := h.log.With().Str("agent_id", agent.AgentID).Str("tenant_id", agent.TenantID).Logger()
+
+	cur, err := h.presence.Get(ctx, agent.TenantID, agent.AgentID)
+	if err != nil {
+		return status.Error(codes.Internal, "ajan durumu okunamadı")
+	}
+	if cur.SessionID == "" || cur.SessionID != req.SessionId {
+		return status.Error(codes.FailedPrecondition, "oturum geçersiz, yeniden giriş yapın")
+	}
+
+	sub := h.queue.SubscribeOffers(ctx, agent.TenantID, agent.AgentID)
+	defer sub.Close()
+	offers := sub.Channel()
+
+	keepAlive := time.Duration(h.cfg.PresenceTTLSeconds) * time.Second / 3
+	if keepAlive <= 0 {
+		keepAlive = 10 * time.Second
+	}
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	l.Info().Str("event", "AGENT_OFFER_STREAM_OPEN").Msg("📡 Ajan teklif akışı açıldı.")
+	defer l.Info().Str("event", "AGENT_OFFER_STREAM_CLOSED").Msg("Ajan teklif akışı kapandı.")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			_ = h.presence.Touch(ctx, agent.TenantID, agent.AgentID)
+		case msg, ok := <-offers:
+			if !ok {
+				return status.Error(codes.Unavailable, "teklif kanalı kapandı")
+			}
+			o, err := callqueue.DecodeOffer(msg.Payload)
+			if err != nil {
+				continue
+			}
+			if err := stream.Send(&workspacev1.QueueOffer{
+				OfferId:    o.ID,
+				Kind:       o.Entry.Kind,
+				CallId:     o.Entry.CallID,
+				QueueId:    o.Entry.QueueID,
+				FromUri:    o.Entry.FromURI,
+				EnqueuedAt: timestamppb.New(o.Entry.EnqueuedAt),
+				Priority:   int32(o.Entry.Priority),
+				OfferedAt:  timestamppb.New(o.OfferedAt),
+				ExpiresAt:  timestamppb.New(o.ExpiresAt),
+				Revoked:    o.Revoked,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AcceptOffer, teklif edilen üyeyi ajana bağlar (canlı çağrı devri veya geri arama).
+func (h *CallHandler) AcceptOffer(ctx context.Context, req *workspacev1.OfferDecisionRequest) (*workspacev1.OfferDecisionResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := h.queue.TakeOffer(ctx, agent.TenantID, agent.AgentID, req.OfferId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "teklif okunamadı")
+	}
+	if o == nil {
+		return &workspacev1.OfferDecisionResponse{Accepted: false, ErrorMessage: "teklif geçerli değil"}, nil
+	}
+
+	h.log.Info().Str("event", "AGENT_OFFER_ACCEPTED").Str("agent_id", agent.AgentID).Str("offer_id", o.ID).Str("entry_id", o.Entry.ID).Msg("✅ Ajan teklifi kabul etti.")
+	_ = h.presence.ResetMisses(ctx, agent.TenantID, agent.AgentID)
+	opCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	h.assignEntry(opCtx, o.Entry, o.Score, o.AgentID)
+	return &workspacev1.OfferDecisionResponse{Accepted: true}, nil
+}
+
+// RejectOffer, teklifi reddeder: üye eski sırasıyla kuyruğa döner, ajan boşta sırasının sonuna geçer.
+func (h *CallHandler) RejectOffer(ctx context.Context, req *workspacev1.OfferDecisionRequest) (*workspacev1.OfferDecisionResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := h.queue.TakeOffer(ctx, agent.TenantID, agent.AgentID, req.OfferId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "teklif okunamadı")
+	}
+	if o == nil {
+		return &workspacev1.OfferDecisionResponse{Accepted: false, ErrorMessage: "teklif geçerli değil"}, nil
+	}
+
+	h.log.Info().Str("event", "AGENT_OFFER_REJECTED").Str("agent_id", agent.AgentID).Str("offer_id", o.ID).Str("reason", req.Reason).Msg("🙅 Ajan teklifi reddetti.")
+	h.withdrawOffer(ctx, o)
+	return &workspacev1.OfferDecisionResponse{Accepted: false}, nil
+}
+
+// offerTimeout, kuyruğun teklif cevap süresini döner; kuyruğa özel ayar yoksa (veya okunamazsa) varsayılan kullanılır.
+func (h *CallHandler) offerTimeout(ctx context.Context, tenantID, queueID string) time.Duration {
+	seconds := h.cfg.OfferTimeoutSeconds
+	if v, ok, err := database.GetOfferTimeoutSeconds(ctx, h.db, tenantID, queueID); err != nil {
+		h.log.Warn().Str("event", "QUEUE_SETTINGS_READ_FAILED").Str("tenant_id", tenantID).Str("queue_id", queueID).Err(err).Msg("Teklif süresi okunamadı, varsayılan kullanılıyor.")
+	} else if ok {
+		seconds = v
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// offerEntry, kuyruktan alınan üyeyi ajana sunar. Masaüstü oturumu olmayan (olaylarla yönetilen) ajanlara
+// üye doğrudan atanır.
+func (h *CallHandler) offerEntry(ctx context.Context, e callqueue.Entry, score float64, agentID string) {
+	a, err := h.presence.Get(ctx, e.TenantID, agentID)
+	if err != nil || a.SessionID == "" {
+		h.assignEntry(ctx, e, score, agentID)
+		return
+	}
+
+	timeout := h.offerTimeout(ctx, e.TenantID, e.QueueID)
+	now := time.Now()
+	o := callqueue.Offer{
+		ID:        newID("offer-"),
+		TenantID:  e.TenantID,
+		AgentID:   agentID,
+		Entry:     e,
+		Score:     score,
+		OfferedAt: now,
+		ExpiresAt: now.Add(timeout),
+	}
+	l := h.log.With().Str("agent_id", agentID).Str("offer_id", o.ID).Str("entry_id", e.ID).Logger()
+
+	receivers, err := h.queue.PublishOffer(ctx, o)
+	if err != nil || receivers == 0 {
+		// Ajanın açık akışı yok; ajan teklifi hiç görmediğinden cevapsız sayılmaz, üye sırasına döner.
+		l.Warn().Str("event", "AGENT_OFFER_UNDELIVERED").Err(err).Msg("Teklif ajana ulaştırılamadı.")
+		if taken, _ := h.queue.TakeOffer(ctx, o.TenantID, o.AgentID, o.ID); taken != nil {
+			h.withdrawOffer(ctx, taken)
+		}
+		return
+	}
+	l.Info().Str("event", "AGENT_OFFERED").Dur("timeout", timeout).Msg("📨 Kuyruk üyesi ajana teklif edildi.")
+}
+
+// withdrawOffer, reddedilen teklifin üyesini kuyruğa geri koyar ve ajanı serbest bırakır.
+func (h *CallHandler) withdrawOffer(ctx context.Context, o *callqueue.Offer) {
+	h.releaseAgent(ctx, o.TenantID, o.AgentID)
+	h.requeueOffered(ctx, o)
+}
+
+// expireOffers, süresi içinde cevaplanmayan teklifleri geri çeker. Dağıtıcı her turda çağırır.
+func (h *CallHandler) expireOffers(ctx context.Context) {
+	due, err := h.queue.DueOffers(ctx, time.Now())
+	if err != nil {
+		return
+	}
+	for _, ref := range due {
+		o, err := h.queue.TakeOffer(ctx, ref.TenantID, ref.AgentID, ref.OfferID)
+		if err != nil || o == nil {
+			continue
+		}
+		_ = h.queue.NotifyOfferRevoked(ctx, *o)
+		h.missOffer(ctx, o, "no_answer")
+	}
+}
+
+// missedStatus, cevapsız teklif sonrası ajanın geçeceği durumdur (AGENT_OFFER_MISSED_STATUS).
+func (h *CallHandler) missedStatus() string {
+	switch h.cfg.OfferMissedStatus {
+	case presence.StatusOnline, presence.StatusBreak:
+		return h.cfg.OfferMissedStatus
+	default:
+		return presence.StatusMissed
+	}
+}
+
+// missOffer, cevapsız teklifi işler: üye sırasıyla kuyruğa dönüp sıradaki ajana teklif edilir, ajan yardımcı
+// duruma alınır. Art arda cevapsız sayısı sınırı aşan ajan zorla BREAK'e alınır.
+func (h *CallHandler) missOffer(ctx context.Context, o *callqueue.Offer, reason string) {
+	st := h.missedStatus()
+	misses, err := h.presence.RecordMiss(ctx, o.TenantID, o.AgentID)
+	forced := err == nil && h.cfg.OfferMaxConsecutiveMisses > 0 && misses >= int64(h.cfg.OfferMaxConsecutiveMisses)
+	if forced {
+		st = presence.StatusBreak
+		_ = h.presence.ResetMisses(ctx, o.TenantID, o.AgentID)
+	}
+	if err := h.presence.ReleaseTo(ctx, o.TenantID, o.AgentID, st); err != nil {
+		h.log.Warn().Str("event", "AGENT_RELEASE_FAILED").Str("agent_id", o.AgentID).Err(err).Msg("Ajan durumu güncellenemedi.")
+	}
+	h.requeueOffered(ctx, o)
+
+	l := h.log.With().Str("agent_id", o.AgentID).Str("offer_id", o.ID).Str("entry_id", o.Entry.ID).Logger()
+	if forced {
+		l.Warn().Str("event", "AGENT_FORCED_BREAK").Int64("consecutive_misses", misses).Msg("⛔ Ajan art arda teklifleri cevapsız bıraktı, molaya alındı.")
+	} else {
+		l.Info().Str("event", "AGENT_OFFER_MISSED").Str("reason", reason).Int64("consecutive_misses", misses).Str("status", st).Msg("⏰ Teklif cevapsız kaldı, sıradaki ajana sunulacak.")
+	}
+	h.publishGenericEvent(ctx, constants.EventTypeOfferMissed, &state.CallState{CallID: o.Entry.CallID, TraceID: o.Entry.CallID, TenantID: o.TenantID}, map[string]interface{}{
+		"tenantId":          o.TenantID,
+		"agentId":           o.AgentID,
+		"offerId":           o.ID,
+		"callId":            o.Entry.CallID,
+		"queueId":           o.Entry.QueueID,
+		"reason":            reason,
+		"consecutiveMisses": misses,
+		"status":            st,
+		"forcedBreak":       forced,
+	})
+}
+
+// requeueOffered, teklif edilen üyeyi eski skoruyla kuyruğa geri koyar.
+func (h *CallHandler) requeueOffered(ctx context.Context, o *callqueue.Offer) {
+	if o.Entry.Kind == callqueue.KindCall {
+		// Arayan bu arada kapattıysa çağrı kuyruğa geri konmaz.
+		if s, err := h.stateManager.Get(ctx, o.Entry.CallID); err != nil || s == nil {
+			return
+		}
+	}
+	if err := h.queue.Requeue(ctx, o.Entry, o.Score); err != nil {
+		h.log.Error().Str("event", "QUEUE_REQUEUE_FAILED").Str("entry_id", o.Entry.ID).Err(err).Msg("❌ Teklif edilen üye kuyruğa geri konamadı.")
+	}
+}
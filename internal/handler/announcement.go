@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/announcement"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+)
+
+func announcementRequest(s *state.CallState, id constants.AnnouncementID) announcement.Request {
+	return announcement.Request{
+		CallID:       s.CallID,
+		TenantID:     s.TenantID,
+		LanguageCode: s.LanguageCode,
+		ID:           id,
+	}
+}
+
+// playAnnouncement, anonsu çaldırır ve bitişini beklemez (pipeline konuşmaya devam ederken kullanılır).
+func (h *CallHandler) playAnnouncement(ctx context.Context, s *state.CallState, id constants.AnnouncementID) error {
+	return h.announcements.Play(ctx, announcementRequest(s, id))
+}
+
+// playAnnouncementAndWait, akış anonsun bitişine bağlıysa (yeniden başlatma, kapanış, statik anons) kullanılır.
+// Bitiş olayı zamanında gelmezse akışın takılmaması için uyarı verilip devam edilir.
+func (h *CallHandler) playAnnouncementAndWait(ctx context.Context, s *state.CallState, id constants.AnnouncementID) error {
+	err := h.announcements.PlayAndWait(ctx, announcementRequest(s, id))
+	if errors.Is(err, announcement.ErrPlaybackTimeout) {
+		h.log.Warn().Str("event", "PLAYBACK_FINISH_TIMEOUT").Str("call_id", s.CallID).Str("announcement_id", string(id)).Msg("Anons bitiş olayı gelmedi, akış devam ediyor.")
+		return nil
+	}
+	if errors.Is(err, announcement.ErrPlaybackUnobserved) {
+		return nil
+	}
+	return err
+}
+
+// farewellGrace, bitişi dinlenemeyen veda anonsu için kapatmadan önce tanınan süredir.
+const farewellGrace = 5 * time.Second
+
+// playFarewell, çağrı kapatılmadan önce çalınan anonsları sırayla çalar ve her birinin bitişini bekler;
+// kapatma anonsu kesmesin diye bitiş dinlenemezse farewellGrace kadar beklenir.
+func (h *CallHandler) playFarewell(ctx context.Context, s *state.CallState, ids ...constants.AnnouncementID) {
+	for _, id := range ids {
+		err := h.announcements.PlayAndWait(ctx, announcementRequest(s, id))
+		switch {
+		case err == nil:
+		case errors.Is(err, announcement.ErrPlaybackUnobserved):
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(farewellGrace):
+			}
+		case errors.Is(err, announcement.ErrPlaybackTimeout):
+			h.log.Warn().Str("event", "PLAYBACK_FINISH_TIMEOUT").Str("call_id", s.CallID).Str("announcement_id", string(id)).Msg("Anons bitiş olayı gelmedi, akış devam ediyor.")
+		default:
+			h.log.Warn().Str("event", "ANNOUNCEMENT_PLAY_FAILED").Str("call_id", s.CallID).Str("announcement_id", string(id)).Err(err).Msg("Anons çalınamadı.")
+		}
+	}
+}
+
+// handlePlayStaticAnnouncement, PLAY_STATIC_ANNOUNCEMENT aksiyonunu yürütür: anonsu çalar, bitişini bekler
+// ve action_data'daki on_finish değerine göre devam eder (hangup: varsayılan, ai: AI konuşması, none: bekle).
+func (h *CallHandler) handlePlayStaticAnnouncement(s *state.CallState, actionData map[string]string) {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+
+	id := constants.AnnouncementID(actionData["announcement_id"])
+	if id == "" {
+		l.Error().Str("event", "ANNOUNCEMENT_ID_MISSING").Msg("PLAY_STATIC_ANNOUNCEMENT aksiyonunda announcement_id yok.")
+		return
+	}
+
+	// Olay tüketicisini bloklamamak için akış ayrı goroutine'de, çağrı ömrüyle sınırlı bir context ile yürür.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		if err := h.playAnnouncementAndWait(ctx, s, id); err != nil {
+			l.Error().Str("event", "ANNOUNCEMENT_PLAY_FAILED").Str("announcement_id", string(id)).Err(err).Msg("Statik anons çalınamadı.")
+			h.compensate(context.Background(), s.CallID, "ANNOUNCEMENT_FAILED")
+			return
+		}
+
+		switch actionData["on_finish"] {
+		case "none":
+			l.Info().Str("event", "ANNOUNCEMENT_FINISHED").Msg("📢 Statik anons tamamlandı.")
+		case "ai":
+			l.Info().Str("event", "ANNOUNCEMENT_FINISHED").Msg("📢 Statik anons tamamlandı, AI konuşmasına geçiliyor.")
+			h.runTASPipeline(ctx, s, actionData)
+		default:
+			l.Info().Str("event", "ANNOUNCEMENT_FINISHED").Msg("📢 Statik anons tamamlandı, çağrı sonlandırılıyor.")
+			h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
+		}
+	}()
+}
+
+// HandlePlaybackFinished, medya katmanının çalma bitiş olayını, bitişi beklenen çalmaya iletir.
+func (h *CallHandler) HandlePlaybackFinished(ctx context.Context, event *eventv1.GenericEvent) {
+	var payload struct {
+		CallID      string `json:"callId"`
+		CallIDAlt   string `json:"call_id"`
+		AudioURI    string `json:"audioUri"`
+		AudioURIAlt string `json:"audio_uri"`
+	}
+	if err := json.Unmarshal([]byte(event.PayloadJson), &payload); err != nil {
+		h.log.Warn().Str("event", "PLAYBACK_EVENT_INVALID").Err(err).Msg("call.media.playback.finished yükü çözümlenemedi.")
+		return
+	}
+	callID := cmp.Or(payload.CallID, payload.CallIDAlt)
+	if callID == "" {
+		h.log.Warn().Str("event", "PLAYBACK_EVENT_INVALID").Msg("call.media.playback.finished olayında call_id yok.")
+		return
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := h.announcements.NotifyPlaybackFinished(opCtx, callID, cmp.Or(payload.AudioURI, payload.AudioURIAlt)); err != nil {
+		h.log.Warn().Str("event", "PLAYBACK_NOTIFY_FAILED").Str("call_id", callID).Err(err).Msg("Çalma bitişi bekleyenlere iletilemedi.")
+	}
+}
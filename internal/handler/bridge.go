@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/metrics"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	sipv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/sip/v1"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+// Köprü sonuçları. Sonuç bir kez yazılır; ilk yazan (zaman aşımı, B-leg olayı) akışı sahiplenir.
+const (
+	bridgeOutcomeAnswered  = "ANSWERED"
+	bridgeOutcomeBusy      = "BUSY"
+	bridgeOutcomeNoAnswer  = "NO_ANSWER"
+	bridgeOutcomeFailed    = "FAILED"
+	bridgeOutcomeCompleted = "COMPLETED"
+)
+
+// errBridgeSettled, köprü sonucunun başka bir akış tarafından zaten belirlendiğini bildirir.
+var errBridgeSettled = errors.New("bridge outcome already settled")
+
+// handleBridgeCall, BRIDGE_CALL aksiyonunu yürütür: B2BUA ile hedefe ikinci bacağı (B-leg) açar ve
+// cevap/zaman aşımı/meşgul sonucunu izler. Medya köprüsü B-leg cevaplandığında TAS üzerinden kurulur.
+func (h *CallHandler) handleBridgeCall(ctx context.Context, s *state.CallState, actionData map[string]string) {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+
+	target := actionData["target_uri"]
+	if target == "" {
+		l.Error().Str("event", "BRIDGE_TARGET_MISSING").Msg("BRIDGE_CALL aksiyonunda target_uri yok.")
+		s.Bridge = &state.BridgeLeg{StartedAt: time.Now(), ActionData: actionData}
+		h.settleBridge(ctx, s, bridgeOutcomeFailed)
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := h.clients.B2BUA.InitiateCall(reqCtx, &sipv1.InitiateCallRequest{
+		CallId:  s.CallID,
+		FromUri: s.FromURI,
+		ToUri:   target,
+	})
+	if err == nil && (!resp.Success || resp.NewCallId == "") {
+		err = errors.New("B2BUA çağrıyı başlatamadı")
+	}
+
+	s.Bridge = &state.BridgeLeg{TargetURI: target, StartedAt: time.Now(), ActionData: actionData}
+	if err != nil {
+		l.Error().Str("event", "BRIDGE_INITIATE_FAILED").Str("target_uri", target).Err(err).Msg("❌ Köprü bacağı açılamadı.")
+		h.settleBridge(ctx, s, bridgeOutcomeFailed)
+		return
+	}
+
+	s.Bridge.PeerCallID = resp.NewCallId
+	s.CurrentState = constants.StateBridging
+	bridge := *s.Bridge
+	if _, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		cs.Bridge = &bridge
+		cs.CurrentState = constants.StateBridging
+		return nil
+	}); err != nil {
+		l.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Köprü durumu kaydedilemedi.")
+	}
+	if err := h.stateManager.SetBridgePeer(ctx, resp.NewCallId, s.CallID); err != nil {
+		l.Warn().Str("event", "BRIDGE_PEER_SAVE_FAILED").Err(err).Msg("B-leg eşlemesi kaydedilemedi.")
+	}
+
+	ringTimeout := time.Duration(h.cfg.BridgeRingTimeoutSeconds) * time.Second
+	if v, err := strconv.Atoi(actionData["ring_timeout_seconds"]); err == nil && v > 0 {
+		ringTimeout = time.Duration(v) * time.Second
+	}
+
+	l.Info().Str("event", "BRIDGE_RINGING").Str("peer_call_id", resp.NewCallId).Str("target_uri", target).Dur("ring_timeout", ringTimeout).Msg("📞 Hedef aranıyor.")
+	h.ringWatches.start(resp.NewCallId, ringTimeout, func() { h.onBridgeRingTimeout(s.CallID, resp.NewCallId) })
+}
+
+// onBridgeRingTimeout, B-leg zaman aşımı içinde cevaplanmadığında bacağı kapatıp no-answer fallback'ini çalıştırır.
+// Bacak cevaplanır, kapanır veya arayan kapatırsa izleyici iptal edildiğinden çağrılmaz.
+func (h *CallHandler) onBridgeRingTimeout(callID, peerCallID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, err := h.claimBridgeOutcome(ctx, callID, bridgeOutcomeNoAnswer)
+	if err != nil {
+		return
+	}
+	h.log.Info().Str("event", "BRIDGE_NO_ANSWER").Str("call_id", callID).Str("peer_call_id", peerCallID).Msg("⏰ Hedef zamanında cevap vermedi.")
+	h.terminateLeg(ctx, peerCallID, "no_answer")
+	h.runBridgeFallback(s, bridgeOutcomeNoAnswer)
+}
+
+// claimBridgeOutcome, köprü hâlâ çalıyorsa sonucu atomik olarak yazar ve güncel durumu döner.
+func (h *CallHandler) claimBridgeOutcome(ctx context.Context, callID, outcome string) (*state.CallState, error) {
+	return h.stateManager.Update(ctx, callID, func(cs *state.CallState) error {
+		if cs.Bridge == nil || cs.Bridge.Outcome != "" {
+			return errBridgeSettled
+		}
+		cs.Bridge.Outcome = outcome
+		if outcome == bridgeOutcomeAnswered {
+			cs.Bridge.AnsweredAt = time.Now()
+			cs.CurrentState = constants.StateBridged
+		}
+		return nil
+	})
+}
+
+// handleBridgePeerStarted, cevaplanan B-leg'in call.started olayını yakalar ve iki bacağın medyasını birleştirir.
+// Olay bir köprüye ait değilse false döner ve normal dialplan akışı devam eder.
+func (h *CallHandler) handleBridgePeerStarted(ctx context.Context, peerCallID string) bool {
+	callID, err := h.stateManager.GetBridgePeer(ctx, peerCallID)
+	if err != nil || callID == "" {
+		return false
+	}
+	h.ringWatches.stop(peerCallID)
+	l := h.log.With().Str("call_id", callID).Str("peer_call_id", peerCallID).Logger()
+
+	s, err := h.claimBridgeOutcome(ctx, callID, bridgeOutcomeAnswered)
+	if err != nil {
+		// Zaman aşımı veya A-leg kapanışı önce davrandı; geç cevaplanan bacak açık bırakılmaz.
+		l.Info().Str("event", "BRIDGE_LATE_ANSWER").Err(err).Msg("Köprü sonuçlanmışken B-leg cevaplandı, bacak kapatılıyor.")
+		h.terminateLeg(ctx, peerCallID, "bridge_settled")
+		return true
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := h.clients.TelephonyAction.BridgeCall(reqCtx, &telephonyv1.BridgeCallRequest{
+		CallIdA: callID,
+		CallIdB: peerCallID,
+	})
+	if err == nil && !resp.Success {
+		err = errors.New(resp.Message)
+	}
+	if err != nil {
+		l.Error().Str("event", "BRIDGE_MEDIA_FAILED").Err(err).Msg("❌ Medya köprüsü kurulamadı.")
+		_, _ = h.stateManager.Update(ctx, callID, func(cs *state.CallState) error {
+			if cs.Bridge != nil {
+				cs.Bridge.Outcome, cs.Bridge.AnsweredAt = bridgeOutcomeFailed, time.Time{}
+			}
+			return nil
+		})
+		s.Bridge.Outcome, s.Bridge.AnsweredAt = bridgeOutcomeFailed, time.Time{}
+		h.terminateLeg(ctx, peerCallID, "bridge_failed")
+		h.runBridgeFallback(s, bridgeOutcomeFailed)
+		return true
+	}
+
+	metrics.BridgeOutcomes.WithLabelValues(bridgeOutcomeAnswered).Inc()
+	l.Info().Str("event", "BRIDGE_ESTABLISHED").Str("target_uri", s.Bridge.TargetURI).Msg("🔗 Çağrı hedefe köprülendi.")
+	return true
+}
+
+// handleBridgePeerEnded, B-leg'in call.ended olayını işler. Cevaplanmadan kapanan bacak meşgul/cevapsız
+// fallback'ini tetikler; konuşma sonrası kapanan bacak süreyi kaydedip A-leg'i de sonlandırır.
+// Olay bir köprüye ait değilse false döner.
+func (h *CallHandler) handleBridgePeerEnded(ctx context.Context, peerCallID, reason string) bool {
+	callID, err := h.stateManager.GetBridgePeer(ctx, peerCallID)
+	if err != nil || callID == "" {
+		return false
+	}
+	_ = h.stateManager.DeleteBridgePeer(ctx, peerCallID)
+	h.ringWatches.stop(peerCallID)
+	l := h.log.With().Str("call_id", callID).Str("peer_call_id", peerCallID).Str("reason", reason).Logger()
+
+	outcome := bridgeOutcomeForReason(reason)
+	if s, err := h.claimBridgeOutcome(ctx, callID, outcome); err == nil {
+		l.Info().Str("event", "BRIDGE_NOT_ANSWERED").Str("outcome", outcome).Msg("📵 Hedef çağrıyı cevaplamadı.")
+		h.runBridgeFallback(s, outcome)
+		return true
+	}
+
+	s, err := h.stateManager.Update(ctx, callID, func(cs *state.CallState) error {
+		if cs.Bridge == nil || cs.Bridge.Outcome != bridgeOutcomeAnswered {
+			return errBridgeSettled
+		}
+		cs.Bridge.Outcome = bridgeOutcomeCompleted
+		return nil
+	})
+	if err != nil {
+		return true
+	}
+	l.Info().Str("event", "BRIDGE_PEER_HANGUP").Msg("Hedef taraf kapattı, çağrı sonlandırılıyor.")
+	h.recordBridge(ctx, s, time.Now())
+	h.compensate(context.Background(), callID, "NORMAL_CLEARING")
+	return true
+}
+
+// endBridgeForCaller, köprülü bir çağrıda arayan kapattığında B-leg'i kapatır ve köprüyü kaydeder.
+func (h *CallHandler) endBridgeForCaller(ctx context.Context, s *state.CallState) {
+	b := s.Bridge
+	if b == nil || b.PeerCallID == "" {
+		return
+	}
+	_ = h.stateManager.DeleteBridgePeer(ctx, b.PeerCallID)
+	h.ringWatches.stop(b.PeerCallID)
+
+	switch b.Outcome {
+	case "":
+		b.Outcome = bridgeOutcomeNoAnswer
+		h.terminateLeg(ctx, b.PeerCallID, "caller_hangup")
+		h.recordBridge(ctx, s, time.Now())
+	case bridgeOutcomeAnswered:
+		b.Outcome = bridgeOutcomeCompleted
+		h.terminateLeg(ctx, b.PeerCallID, "caller_hangup")
+		h.recordBridge(ctx, s, time.Now())
+	}
+}
+
+// settleBridge, B-leg hiç açılamadığında sonucu kaydedip fallback'i çalıştırır.
+func (h *CallHandler) settleBridge(ctx context.Context, s *state.CallState, outcome string) {
+	s.Bridge.Outcome = outcome
+	bridge := *s.Bridge
+	if _, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		cs.Bridge = &bridge
+		return nil
+	}); err != nil {
+		h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Köprü sonucu kaydedilemedi.")
+	}
+	h.runBridgeFallback(s, outcome)
+}
+
+// runBridgeFallback, cevaplanmayan köprüyü kaydeder ve dialplan'daki fallback'i uygular.
+func (h *CallHandler) runBridgeFallback(s *state.CallState, outcome string) {
+	metrics.BridgeOutcomes.WithLabelValues(outcome).Inc()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	h.recordBridge(ctx, s, time.Now())
+
+	// Fallback (kuyruk, anons, AI) köprüden çıkmış çağrıyla başlar; faz Redis'e de yazılır.
+	updated, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		cs.CurrentState = constants.StateWelcoming
+		return nil
+	})
+	switch {
+	case errors.Is(err, state.ErrStateNotFound):
+		return
+	case err != nil:
+		h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Köprü sonrası faz kaydedilemedi.")
+		s.CurrentState = constants.StateWelcoming
+	default:
+		s = updated
+	}
+	h.runFallback(context.Background(), s, s.Bridge.ActionData, strings.ToLower(outcome))
+}
+
+func (h *CallHandler) recordBridge(ctx context.Context, s *state.CallState, endedAt time.Time) {
+	b := s.Bridge
+	err := database.RecordBridge(ctx, h.db, s.CallID, database.BridgeRecord{
+		TargetURI:  b.TargetURI,
+		Outcome:    b.Outcome,
+		AnsweredAt: b.AnsweredAt,
+		EndedAt:    endedAt,
+	})
+	if err != nil {
+		h.log.Warn().Str("event", "BRIDGE_RECORD_FAILED").Str("call_id", s.CallID).Err(err).Msg("Köprü süresi konuşmaya yazılamadı.")
+	}
+}
+
+// terminateLeg, TAS üzerinden tek bir çağrı bacağını sonlandırır.
+func (h *CallHandler) terminateLeg(ctx context.Context, callID, reason string) {
+	reqCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if _, err := h.clients.TelephonyAction.TerminateCall(reqCtx, &telephonyv1.TerminateCallRequest{CallId: callID, Reason: reason}); err != nil {
+		h.log.Warn().Str("event", "LEG_TERMINATE_FAILED").Str("call_id", callID).Err(err).Msg("Çağrı bacağı sonlandırılamadı.")
+	}
+}
+
+// bridgeOutcomeForReason, cevaplanmadan kapanan B-leg'in sonlanma nedenini köprü sonucuna eşler.
+func bridgeOutcomeForReason(reason string) string {
+	r := strings.ToLower(reason)
+	switch {
+	case strings.Contains(r, "busy"):
+		return bridgeOutcomeBusy
+	case strings.Contains(r, "fail"), strings.Contains(r, "error"), strings.Contains(r, "unavailable"), strings.Contains(r, "not_found"):
+		return bridgeOutcomeFailed
+	default:
+		return bridgeOutcomeNoAnswer
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// routeOutOfHours, kuyruğa almadan önce tenant takvimini kontrol eder. Çağrı mesai dışındaysa kararı
+// CallState'e yazar, takvimin (veya action_data.out_of_hours_action'ın) aksiyonunu uygular ve true döner.
+// Takvim okunamazsa çağrı kuyruğa alınır (fail-open).
+func (h *CallHandler) routeOutOfHours(ctx context.Context, s *state.CallState, scheduleID string, actionData map[string]string) bool {
+	l := h.log.With().Str("call_id", s.CallID).Str("tenant_id", s.TenantID).Logger()
+
+	st, err := h.businessHours.Check(ctx, s.TenantID, scheduleID, time.Now())
+	if err != nil {
+		l.Warn().Str("event", "BUSINESS_HOURS_CHECK_FAILED").Err(err).Msg("Çalışma saatleri kontrol edilemedi, çağrı kuyruğa alınıyor.")
+		return false
+	}
+	if st.Open {
+		return false
+	}
+
+	action := st.Action
+	if v := actionData["out_of_hours_action"]; v != "" {
+		action = v
+	}
+	decision := &state.OutOfHoursDecision{
+		ScheduleID:  st.ScheduleID,
+		Reason:      st.Reason,
+		HolidayName: st.HolidayName,
+		Action:      action,
+		CheckedAt:   time.Now(),
+	}
+	s.OutOfHours = decision
+	if _, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		cs.OutOfHours = decision
+		return nil
+	}); err != nil {
+		l.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Mesai dışı kararı kaydedilemedi.")
+	}
+
+	l.Info().Str("event", "OUT_OF_HOURS").
+		Str("schedule_id", st.ScheduleID).
+		Str("timezone", st.Timezone).
+		Str("reason", st.Reason).
+		Str("holiday", st.HolidayName).
+		Str("action", action).
+		Msg("🌙 Çağrı mesai dışında, kuyruk yerine alternatif aksiyon uygulanıyor.")
+
+	data := make(map[string]string, len(actionData)+2)
+	for k, v := range actionData {
+		data[k] = v
+	}
+	data["out_of_hours_fallback"] = action
+	if data["fallback_announcement_id"] == "" {
+		data["fallback_announcement_id"] = st.AnnouncementID
+	}
+	h.runFallback(ctx, s, data, "out_of_hours")
+	return true
+}
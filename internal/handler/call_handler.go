@@ -5,40 +5,68 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"io"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/sentiric/sentiric-agent-service/internal/announcement"
+	"github.com/sentiric/sentiric-agent-service/internal/businesshours"
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
 	"github.com/sentiric/sentiric-agent-service/internal/client"
+	"github.com/sentiric/sentiric-agent-service/internal/config"
 	"github.com/sentiric/sentiric-agent-service/internal/constants"
 	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/presence"
+	"github.com/sentiric/sentiric-agent-service/internal/prompt"
 	"github.com/sentiric/sentiric-agent-service/internal/queue"
+	"github.com/sentiric/sentiric-agent-service/internal/queuestats"
 	"github.com/sentiric/sentiric-agent-service/internal/state"
-	agentv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/agent/v1"
+	"github.com/sentiric/sentiric-agent-service/internal/voicemodel"
 	dialplanv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/dialplan/v1"
 	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
 	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
-	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type CallHandler struct {
-	clients      *client.Clients
-	stateManager *state.Manager
-	publisher    *queue.RabbitMQ // BURASI DEĞİŞTİ
-	db           *sql.DB
-	log          zerolog.Logger
+	cfg           *config.Config
+	clients       *client.Clients
+	stateManager  *state.Manager
+	publisher     *queue.RabbitMQ // BURASI DEĞİŞTİ
+	db            *sql.DB
+	transcripts   *database.TranscriptWriter
+	models        *voicemodel.Selector
+	announcements *announcement.Service
+	prompts       *prompt.Engine
+	businessHours *businesshours.Checker
+	queue         *callqueue.Queue
+	presence      *presence.Store
+	stats         *queuestats.Engine
+	ringWatches   legWatches
+	callScopes    callScopes
+	// pipelines, bu replikada süren TAS pipeline akışlarının context'leridir; devir tamamlanınca veya çağrı bitince iptal edilir.
+	pipelines callScopes
+	log       zerolog.Logger
 }
 
-func NewCallHandler(clients *client.Clients, sm *state.Manager, pub *queue.RabbitMQ, db *sql.DB, log zerolog.Logger) *CallHandler {
+func NewCallHandler(cfg *config.Config, clients *client.Clients, sm *state.Manager, pub *queue.RabbitMQ, db *sql.DB, transcripts *database.TranscriptWriter, models *voicemodel.Selector, announcements *announcement.Service, prompts *prompt.Engine, businessHours *businesshours.Checker, callQueue *callqueue.Queue, presenceStore *presence.Store, queueStats *queuestats.Engine, log zerolog.Logger) *CallHandler {
 	return &CallHandler{
-		clients:      clients,
-		stateManager: sm,
-		publisher:    pub,
-		db:           db,
-		log:          log,
+		cfg:           cfg,
+		clients:       clients,
+		stateManager:  sm,
+		publisher:     pub,
+		db:            db,
+		transcripts:   transcripts,
+		models:        models,
+		announcements: announcements,
+		prompts:       prompts,
+		businessHours: businessHours,
+		queue:         callQueue,
+		presence:      presenceStore,
+		stats:         queueStats,
+		log:           log,
 	}
 }
 
@@ -53,11 +81,25 @@ func (h *CallHandler) RunTASPipelineWithPlan(ctx context.Context, s *state.CallS
 func (h *CallHandler) HandleCallStarted(ctx context.Context, event *eventv1.CallStartedEvent) {
 	l := h.log.With().Str("call_id", event.CallId).Logger()
 
-	lockKey := fmt.Sprintf("lock:agent:%s", event.CallId)
-	isNew, err := h.stateManager.RedisClient().SetNX(ctx, lockKey, "1", 15*time.Second).Result()
-	if err != nil || !isNew {
-		l.Debug().Str("event", "DUPLICATE_EVENT_IGNORED").Msg("Duplicate event ignored.")
-		return
+	// Köprü/danışma/giden arama bacaklarının cevaplanması da call.started olarak gelir; dialplan'a girmez.
+	// Her call.started için bacak türü tek bir Redis round-trip'iyle belirlenir; yalnızca eşleşen işleyici çalışır.
+	leg, err := h.stateManager.ClassifyLeg(ctx, event.CallId)
+	if err != nil {
+		l.Warn().Str("event", "LEG_CLASSIFY_FAILED").Err(err).Msg("Bacak türü belirlenemedi, çağrı yeni çağrı olarak işleniyor.")
+	}
+	switch leg {
+	case state.LegBridgePeer:
+		if h.handleBridgePeerStarted(ctx, event.CallId) {
+			return
+		}
+	case state.LegConsult:
+		if h.handleConsultLegStarted(ctx, event.CallId) {
+			return
+		}
+	case state.LegOutbound:
+		if h.handleOutboundAnswered(ctx, event.CallId) {
+			return
+		}
 	}
 
 	res := event.GetDialplanResolution()
@@ -73,7 +115,7 @@ func (h *CallHandler) HandleCallStarted(ctx context.Context, event *eventv1.Call
 	actionType := res.Action.Type
 	l.Info().Str("event", "DIALPLAN_DECISION").Interface("action_type", actionType).Msg("🧠 Analyzing Dialplan Decision")
 
-	lang := "tr"
+	lang := h.cfg.DefaultLanguageCode
 	if res.InboundRoute != nil && res.InboundRoute.DefaultLanguageCode != "" {
 		lang = res.InboundRoute.DefaultLanguageCode
 	}
@@ -93,22 +135,33 @@ func (h *CallHandler) HandleCallStarted(ctx context.Context, event *eventv1.Call
 		s.ServerRtpPort = event.MediaInfo.ServerRtpPort
 		s.CallerRtpAddr = event.MediaInfo.CallerRtpAddr
 	}
+	if user := h.identifiedUser(res.GetMatchedUser()); user != nil {
+		attachUser(s, user)
+		h.logLanguageSwitch(s, lang)
+	}
 	_ = h.stateManager.Set(ctx, s)
 
 	switch actionType {
 	case dialplanv1.ActionType_ACTION_TYPE_START_AI_CONVERSATION:
 		l.Info().Str("event", "AI_CALL_DETECTED").Msg("🤖 AI Çağrısı Algılandı. Workflow devri bekleniyor...")
 	case dialplanv1.ActionType_ACTION_TYPE_PLAY_STATIC_ANNOUNCEMENT:
-		l.Info().Str("event", "ACTION_PLAY_STATIC").Msg("📢 Action: PLAY_STATIC_ANNOUNCEMENT. Agent görevi yok, izlemede.")
-		return
+		l.Info().Str("event", "ACTION_PLAY_STATIC").Msg("📢 Action: PLAY_STATIC_ANNOUNCEMENT. Anons çalınıyor.")
+		h.handlePlayStaticAnnouncement(s, res.Action.ActionData)
 	case dialplanv1.ActionType_ACTION_TYPE_BRIDGE_CALL:
-		l.Info().Str("event", "ACTION_BRIDGE_CALL").Msg("📞 Action: BRIDGE_CALL. Handed over to SIP Signaling.")
-		s.CurrentState = "BRIDGED"
-		_ = h.stateManager.Set(ctx, s)
+		l.Info().Str("event", "ACTION_BRIDGE_CALL").Msg("📞 Action: BRIDGE_CALL. B2BUA ile hedef aranıyor.")
+		h.handleBridgeCall(ctx, s, res.Action.ActionData)
 	case dialplanv1.ActionType_ACTION_TYPE_ECHO_TEST:
-		l.Info().Str("event", "ACTION_ECHO_TEST").Msg("🔊 Action: ECHO_TEST. Agent in standby mode.")
+		l.Info().Str("event", "ACTION_ECHO_TEST").Msg("🔊 Action: ECHO_TEST. Medya yolu ölçülüyor.")
+		h.handleEchoTest(s, res.Action.ActionData)
 	case dialplanv1.ActionType_ACTION_TYPE_ENQUEUE_CALL:
 		l.Info().Str("event", "ACTION_ENQUEUE_CALL").Msg("👥 Action: ENQUEUE_CALL. Checking agent availability...")
+		scheduleID := res.Action.ActionData["schedule_id"]
+		if scheduleID == "" {
+			scheduleID = res.GetInboundRoute().GetScheduleId()
+		}
+		if h.routeOutOfHours(ctx, s, scheduleID, res.Action.ActionData) {
+			return
+		}
 		h.handleEnqueueCall(ctx, s, res.Action.ActionData)
 	default:
 		l.Warn().Str("event", "UNHANDLED_ACTION").Interface("type", actionType).Msg("⚠️ Unhandled action type received.")
@@ -119,48 +172,48 @@ func (h *CallHandler) handleEnqueueCall(ctx context.Context, s *state.CallState,
 	l := h.log.With().Str("call_id", s.CallID).Logger()
 	targetAgentID, hasTarget := actionData["target_agent_id"]
 
+	// Direct Match: tanınan kullanıcı daha önce bir ajanla görüştüyse ona öncelik ver.
+	if !hasTarget && s.User != nil && actionData["skip_affinity"] != "true" {
+		if agentID, err := h.stateManager.GetAgentAffinity(ctx, s.User.ID); err == nil && agentID != "" {
+			l.Info().Str("event", "AGENT_AFFINITY_MATCH").Str("agent_id", agentID).Str("user_id", s.User.ID).Msg("🔗 Kullanıcının tercihli ajanı bulundu.")
+			targetAgentID, hasTarget = agentID, true
+		}
+	}
+
 	if hasTarget {
-		reqCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
-		defer cancel()
-		profile, err := h.clients.User.GetAgentProfile(reqCtx, &userv1.GetAgentProfileRequest{UserId: targetAgentID})
-		if err == nil && profile.Profile.Status == "ONLINE" {
+		// Uygunluk presence deposundan okunur; ajan ONLINE ise aynı adımda sahiplenilir, böylece dağıtıcı
+		// onu kuyruktan başka bir çağrıya atayamaz.
+		claimed, err := h.presence.Claim(ctx, s.TenantID, targetAgentID)
+		if err == nil && claimed {
 			l.Info().Str("event", "AGENT_ONLINE").Str("agent_id", targetAgentID).Msg("✅ Hedef ajan ONLINE. Transfer başlatılıyor.")
-			s.CurrentState = "TRANSFERRED"
-			_ = h.stateManager.Set(ctx, s)
+			mode := transferModeBlind
+			if strings.EqualFold(actionData["transfer_mode"], transferModeAttended) {
+				mode = transferModeAttended
+			}
+			h.startTransfer(ctx, s, transferRequest{
+				Mode:       mode,
+				TargetURI:  h.agentTargetURI(targetAgentID),
+				AgentID:    targetAgentID,
+				FromQueue:  true,
+				ActionData: actionData,
+			})
 			return
-		} else {
-			l.Warn().Str("event", "AGENT_OFFLINE").Str("agent_id", targetAgentID).Msg("⛔ Hedef ajan OFFLINE veya meşgul. Fallback uygulanıyor.")
 		}
+		l.Warn().Str("event", "AGENT_OFFLINE").Str("agent_id", targetAgentID).Err(err).Msg("⛔ Hedef ajan OFFLINE veya meşgul. Çağrı kuyruğa alınıyor.")
 	}
-	l.Info().Str("event", "QUEUE_MUSIC_STARTED").Msg("🎵 Kuyruk müziği başlatılıyor (Mock).")
+	h.enqueueCall(ctx, s, actionData)
 }
 
 func (h *CallHandler) runTASPipeline(grpcCtx context.Context, s *state.CallState, actionData map[string]string) {
 	l := h.log.With().Str("call_id", s.CallID).Logger()
 
-	voiceID := "coqui:default"
-	if v, ok := actionData["voice_id"]; ok {
-		voiceID = v
-	}
-
-	recordSession := false
-	if r, ok := actionData["record"]; ok && r == "true" {
-		recordSession = true
-	}
-
-	req := &telephonyv1.RunPipelineRequest{
-		CallId:    s.CallID,
-		SessionId: s.TraceID,
-		MediaInfo: &eventv1.MediaInfo{
-			CallerRtpAddr: s.CallerRtpAddr,
-			ServerRtpPort: s.ServerRtpPort,
-		},
-		SttModelId:     "whisper:default",
-		TtsModelId:     voiceID,
-		RecordSession:  recordSession,
-		LanguageCode:   s.LanguageCode,
-		SystemPromptId: actionData["system_prompt_id"],
-	}
+	s.VoiceModels = h.models.Resolve(grpcCtx, s.TenantID, s.LanguageCode, actionData)
+	l.Info().Str("event", "VOICE_MODELS_RESOLVED").
+		Str("stt_model_id", s.VoiceModels.SttModelID).
+		Str("stt_source", s.VoiceModels.SttSource).
+		Str("tts_model_id", s.VoiceModels.TtsModelID).
+		Str("tts_source", s.VoiceModels.TtsSource).
+		Msg("🎛️ STT/TTS modelleri çözümlendi.")
 
 	pipelineCtx, cancel := context.WithTimeout(context.Background(), 24*time.Hour)
 	if s.TraceID != "" {
@@ -175,8 +228,10 @@ func (h *CallHandler) runTASPipeline(grpcCtx context.Context, s *state.CallState
 	}
 	// -------------------------------
 
-	stream, err := h.clients.TelephonyAction.RunPipeline(pipelineCtx, req)
+	pipelineCtx, release := h.pipelines.begin(pipelineCtx, s.CallID)
+	stream, err := h.startPipeline(pipelineCtx, s, actionData)
 	if err != nil {
+		release()
 		cancel()
 		l.Error().Str("event", "TAS_PIPELINE_START_FAIL").Err(err).Msg("❌ SAGA FAILURE: Cannot start TAS Pipeline.")
 		h.compensate(context.Background(), s.CallID, "TAS_UNREACHABLE")
@@ -189,27 +244,38 @@ func (h *CallHandler) runTASPipeline(grpcCtx context.Context, s *state.CallState
 
 	go func() {
 		defer cancel()
-		for {
-			resp, err := stream.Recv()
+		defer release()
+		h.supervisePipeline(pipelineCtx, s, actionData, stream)
+	}()
+}
 
-			if err == io.EOF {
-				l.Info().Str("event", "TAS_PIPELINE_EOF").Msg("🏁 SAGA SUCCESS: Pipeline finished naturally.")
-				h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
-				return
-			}
-			if err != nil {
-				l.Error().Str("event", "TAS_PIPELINE_BROKEN").Err(err).Msg("⚠️ SAGA BREAK: TAS Stream connection lost.")
-				h.compensate(context.Background(), s.CallID, "PIPELINE_BROKEN")
-				return
-			}
+// startPipeline, CallState'teki güncel model seçimiyle TAS üzerinde bir RunPipeline akışı açar.
+func (h *CallHandler) startPipeline(ctx context.Context, s *state.CallState, actionData map[string]string) (telephonyv1.TelephonyActionService_RunPipelineClient, error) {
+	recordSession := false
+	if r, ok := actionData["record"]; ok && r == "true" {
+		recordSession = true
+	}
 
-			if resp.State == telephonyv1.RunPipelineResponse_STATE_ERROR {
-				l.Error().Str("event", "TAS_INTERNAL_ERROR").Str("msg", resp.Message).Msg("❌ SAGA FAILURE: TAS internal error.")
-				h.compensate(context.Background(), s.CallID, "PIPELINE_ERROR")
-				return
-			}
-		}
-	}()
+	req := &telephonyv1.RunPipelineRequest{
+		CallId:    s.CallID,
+		SessionId: s.TraceID,
+		MediaInfo: &eventv1.MediaInfo{
+			CallerRtpAddr: s.CallerRtpAddr,
+			ServerRtpPort: s.ServerRtpPort,
+		},
+		SttModelId:     s.VoiceModels.SttModelID,
+		TtsModelId:     s.VoiceModels.TtsModelID,
+		RecordSession:  recordSession,
+		LanguageCode:   s.LanguageCode,
+		SystemPromptId: string(systemPromptID(actionData)),
+	}
+	// İşlenmiş prompt'lar ve karşılama şablonu (arayanın tanınıp tanınmadığına göre seçilir) sözleşmedeki
+	// CallContext mesajıyla gider; yeniden başlatmada şablonlar yeniden işlenir.
+	if err := attachCallContext(req, h.pipelineCallContext(ctx, s, actionData)); err != nil {
+		h.log.Warn().Str("event", "CALL_CONTEXT_MARSHAL_FAILED").Str("call_id", s.CallID).Err(err).Msg("Çağrı bağlamı kodlanamadı, pipeline şablon kimliğiyle başlatılacak.")
+	}
+
+	return h.clients.TelephonyAction.RunPipeline(ctx, req)
 }
 
 func (h *CallHandler) compensate(ctx context.Context, callID, reason string) {
@@ -240,14 +306,35 @@ func (h *CallHandler) compensate(ctx context.Context, callID, reason string) {
 	_ = h.stateManager.Delete(ctx, callID)
 }
 
-func (h *CallHandler) HandleCallEnded(ctx context.Context, callID string) {
+func (h *CallHandler) HandleCallEnded(ctx context.Context, callID, reason string) {
+	if h.handleBridgePeerEnded(ctx, callID, reason) || h.handleConsultLegEnded(ctx, callID, reason) {
+		return
+	}
+
 	h.log.Info().Str("event", "CALL_ENDED").Str("call_id", callID).Msg("🧹 Call ended. Session cleanup.")
+	h.callScopes.end(callID)
+	h.pipelines.end(callID)
+	if s, err := h.stateManager.Get(ctx, callID); err == nil && s != nil {
+		h.endBridgeForCaller(ctx, s)
+		if t := s.Transfer; t != nil && (t.Status == transferStatusRinging || t.Status == transferStatusConsulting) {
+			h.ringWatches.stop(t.ConsultCallID)
+			_ = h.stateManager.DeleteConsultLeg(ctx, t.ConsultCallID)
+			h.terminateLeg(ctx, t.ConsultCallID, "caller_hangup")
+		}
+		if t := s.Transfer; t != nil && t.FromQueue {
+			if t.Status == transferStatusCompleted {
+				h.startWrapUp(ctx, s.TenantID, t.AgentID, callID)
+			} else {
+				h.releaseAgent(ctx, s.TenantID, t.AgentID)
+			}
+		}
+		h.endOutbound(ctx, s)
+		h.recordQueueHangup(ctx, s)
+	} else {
+		h.leaveQueue(ctx, callID)
+	}
 	if err := database.UpdateConversationStatus(h.db, callID, "COMPLETED"); err != nil {
 		h.log.Warn().Str("event", "DB_UPDATE_FAIL").Err(err).Msg("Konuşma durumu güncellenemedi")
 	}
 	_ = h.stateManager.Delete(ctx, callID)
 }
-
-func (h *CallHandler) ProcessManualDial(ctx context.Context, req *agentv1.ProcessManualDialRequest) (*agentv1.ProcessManualDialResponse, error) {
-	return &agentv1.ProcessManualDialResponse{Accepted: true, CallId: "out-dummy"}, nil
-}
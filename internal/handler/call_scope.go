@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"sync"
+)
+
+// callScopes, çağrı ömrüyle sınırlı arka plan işlerinin (ör. echo testi) context'lerini tutar. Çağrı bu replikada
+// sona erdiğinde (call.ended) çağrıya ait tüm context'ler iptal edilir. Olay başka bir replikaya düşerse işler kendi
+// süre sınırlarıyla biter.
+type callScopes struct {
+	mu     sync.Mutex
+	next   uint64
+	scopes map[string]map[uint64]context.CancelFunc
+}
+
+// begin, çağrı bitince iptal edilen bir context döner. Dönen cancel, iş bittiğinde kaydı silmek için çağrılmalıdır.
+func (c *callScopes) begin(parent context.Context, callID string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	c.mu.Lock()
+	if c.scopes == nil {
+		c.scopes = make(map[string]map[uint64]context.CancelFunc)
+	}
+	if c.scopes[callID] == nil {
+		c.scopes[callID] = make(map[uint64]context.CancelFunc)
+	}
+	c.next++
+	id := c.next
+	c.scopes[callID][id] = cancel
+	c.mu.Unlock()
+
+	return ctx, func() {
+		cancel()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.scopes[callID], id)
+		if len(c.scopes[callID]) == 0 {
+			delete(c.scopes, callID)
+		}
+	}
+}
+
+// end, çağrıya ait tüm context'leri iptal eder.
+func (c *callScopes) end(callID string) {
+	c.mu.Lock()
+	cancels := c.scopes[callID]
+	delete(c.scopes, callID)
+	c.mu.Unlock()
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/queuestats"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	agentv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/agent/v1"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+	sipv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/sip/v1"
+)
+
+// Geri arama durumları (callbacks.status).
+const (
+	callbackStatusPending   = "PENDING"
+	callbackStatusDialing   = "DIALING"
+	callbackStatusConnected = "CONNECTED"
+	callbackStatusNoAnswer  = "NO_ANSWER"
+	callbackStatusFailed    = "FAILED"
+)
+
+const callbackEntryPrefix = "callback:"
+
+var (
+	// errNotQueued, geri arama istenen çağrının artık kuyrukta olmadığını (ör. ajana atandığını) bildirir.
+	errNotQueued = errors.New("call is not waiting in queue")
+	// errNotOutbound, call.started olayının bir giden aramaya ait olmadığını bildirir.
+	errNotOutbound = errors.New("call is not an outbound dial")
+)
+
+// callbackCommand, agent.callback.requested GenericEvent'inin payload_json yapısıdır.
+// CallbackURI boşsa arayanın kendi numarası geri aranır.
+type callbackCommand struct {
+	CallID      string `json:"callId"`
+	CallbackURI string `json:"callbackUri,omitempty"`
+}
+
+// newID, olay ve kayıtlar için rastgele bir kimlik üretir.
+func newID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// HandleCallbackRequested, kuyrukta bekleyen arayanın geri arama talebini işler.
+func (h *CallHandler) HandleCallbackRequested(ctx context.Context, event *eventv1.GenericEvent) {
+	var cmd callbackCommand
+	if err := json.Unmarshal([]byte(event.PayloadJson), &cmd); err != nil || cmd.CallID == "" {
+		h.log.Warn().Str("event", "CALLBACK_COMMAND_INVALID").Err(err).Msg("Geri arama talebi çözümlenemedi.")
+		return
+	}
+	l := h.log.With().Str("call_id", cmd.CallID).Logger()
+
+	opCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	s, err := h.stateManager.Get(opCtx, cmd.CallID)
+	if err != nil || s == nil {
+		l.Warn().Str("event", "CALLBACK_CALL_NOT_FOUND").Err(err).Msg("Geri arama talebi için çağrı durumu bulunamadı.")
+		return
+	}
+	// [ARCH-COMPLIANCE] Tenant izolasyonu: başka tenant adına gelen talep uygulanmaz.
+	if event.TenantId != "" && event.TenantId != s.TenantID {
+		l.Warn().Str("event", "CALLBACK_TENANT_MISMATCH").Str("tenant_id", event.TenantId).Msg("Geri arama talebi çağrının tenant'ına ait değil.")
+		return
+	}
+
+	if err := h.scheduleCallback(opCtx, s, cmd.CallbackURI, nil, queuestats.ExitCallback); err != nil {
+		l.Warn().Str("event", "CALLBACK_REJECTED").Err(err).Msg("Geri arama talebi kabul edilmedi.")
+	}
+}
+
+// scheduleCallback, arayanı geri arama listesine alır ve çağrıyı bir anonsla kapatır. Arayan
+// kuyruktaysa geri arama onun yerini (ve sırasını) alır ve çağrının kuyruk çıkışı exit olarak sayılır; değilse
+// kuyruğun sonuna eklenir.
+func (h *CallHandler) scheduleCallback(ctx context.Context, s *state.CallState, callbackURI string, actionData map[string]string, exit string) error {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+
+	if callbackURI == "" {
+		callbackURI = s.FromURI
+	}
+	if callbackURI == "" {
+		return errors.New("geri aranacak numara yok")
+	}
+
+	id := newID("")
+	e := callqueue.Entry{
+		ID:         callbackEntryPrefix + id,
+		Kind:       callqueue.KindCallback,
+		TenantID:   s.TenantID,
+		CallID:     s.CallID,
+		FromURI:    callbackURI,
+		EnqueuedAt: time.Now(),
+		Priority:   h.callPriority(s, actionData),
+		Language:   s.LanguageCode,
+		ActionData: actionData,
+	}
+
+	var pos int64
+	var err error
+	replaced := false
+	if s.Queue != nil {
+		e.QueueID = s.Queue.QueueID
+		var ok bool
+		pos, ok, err = h.queue.Replace(ctx, s.CallID, e)
+		if err == nil && !ok {
+			return errNotQueued
+		}
+		replaced = ok
+	} else {
+		e.QueueID = actionData["queue_id"]
+		if e.QueueID == "" {
+			e.QueueID = callqueue.DefaultQueueID
+		}
+		pos, err = h.queue.Enqueue(ctx, e)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := database.InsertCallback(ctx, h.db, database.Callback{
+		ID:             id,
+		TenantID:       s.TenantID,
+		QueueID:        e.QueueID,
+		OriginalCallID: s.CallID,
+		CallbackURI:    callbackURI,
+	}); err != nil {
+		l.Warn().Str("event", "DB_CALLBACK_INSERT_FAILED").Err(err).Msg("Geri arama kaydı veritabanına yazılamadı (kuyruk sırası korunuyor).")
+	}
+
+	updated, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		if cs.Queue == nil {
+			cs.Queue = &state.QueueMembership{QueueID: e.QueueID, EnqueuedAt: e.EnqueuedAt}
+		}
+		cs.Queue.CallbackID = id
+		return nil
+	})
+	if errors.Is(err, state.ErrStateNotFound) {
+		// Arayan bu arada kapattı; talep ettiği geri arama kuyrukta kalır, anons çalınacak bir çağrı yoktur.
+		l.Info().Str("event", "CALLBACK_SCHEDULED").Str("callback_id", id).Str("queue_id", e.QueueID).Int64("position", pos).Msg("📲 Geri arama talebi kuyruğa alındı, arayan kapatmış.")
+		h.publishCallbackState(ctx, e, callbackStatusPending, "", "")
+		return nil
+	}
+	if err != nil {
+		l.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Geri arama çağrı durumuna yazılamadı.")
+		if s.Queue == nil {
+			s.Queue = &state.QueueMembership{QueueID: e.QueueID, EnqueuedAt: e.EnqueuedAt}
+		}
+		s.Queue.CallbackID = id
+	} else {
+		s = updated
+	}
+	if replaced {
+		h.recordQueueExit(ctx, s, exit)
+	}
+
+	l.Info().Str("event", "CALLBACK_SCHEDULED").Str("callback_id", id).Str("queue_id", e.QueueID).Int64("position", pos).Msg("📲 Geri arama talebi kuyruğa alındı.")
+	h.publishCallbackState(ctx, e, callbackStatusPending, "", "")
+
+	go func() {
+		playCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		_ = h.playAnnouncementAndWait(playCtx, s, constants.AnnounceCallbackScheduled)
+		h.compensate(context.Background(), s.CallID, "CALLBACK_SCHEDULED")
+	}()
+	return nil
+}
+
+// dialCallback, sırası gelen geri arama için ajan adına giden çağrı başlatır. Çağrı açılamazsa
+// üye deneme hakkı kaldıkça aynı skorla kuyruğa geri döner.
+func (h *CallHandler) dialCallback(ctx context.Context, e callqueue.Entry, score float64, agentID string) {
+	l := h.log.With().Str("entry_id", e.ID).Str("agent_id", agentID).Logger()
+	e.Attempts++
+
+	callID, err := h.originate(ctx, e.TenantID, agentID, e.FromURI, &state.OutboundDial{
+		AgentID:       agentID,
+		Callback:      &e,
+		CallbackScore: score,
+	})
+	if err != nil {
+		l.Warn().Str("event", "CALLBACK_DIAL_FAILED").Int("attempt", e.Attempts).Err(err).Msg("Geri arama çağrısı başlatılamadı.")
+		h.releaseAgent(ctx, e.TenantID, agentID)
+		h.retryCallback(ctx, e, score, callbackStatusFailed)
+		return
+	}
+
+	l.Info().Str("event", "CALLBACK_DIALING").Str("outbound_call_id", callID).Int("attempt", e.Attempts).Msg("📞 Geri arama başlatıldı.")
+	h.updateCallback(ctx, e, callbackStatusDialing, agentID, callID)
+}
+
+// retryCallback, başarısız denemeden sonra geri aramayı kuyruğa döndürür; hak bittiyse son durumu kaydeder.
+func (h *CallHandler) retryCallback(ctx context.Context, e callqueue.Entry, score float64, finalStatus string) {
+	if e.Attempts < h.cfg.CallbackMaxAttempts {
+		if err := h.queue.Requeue(ctx, e, score); err == nil {
+			h.updateCallback(ctx, e, callbackStatusPending, "", "")
+			return
+		}
+	}
+	h.log.Warn().Str("event", "CALLBACK_GAVE_UP").Str("entry_id", e.ID).Int("attempts", e.Attempts).Str("status", finalStatus).Msg("Geri arama deneme hakkı tükendi.")
+	h.updateCallback(ctx, e, finalStatus, "", "")
+}
+
+func (h *CallHandler) updateCallback(ctx context.Context, e callqueue.Entry, status, agentID, outboundCallID string) {
+	id := strings.TrimPrefix(e.ID, callbackEntryPrefix)
+	if err := database.UpdateCallbackStatus(ctx, h.db, id, status, e.Attempts, agentID, outboundCallID); err != nil {
+		h.log.Warn().Str("event", "DB_CALLBACK_UPDATE_FAILED").Str("callback_id", id).Err(err).Msg("Geri arama durumu güncellenemedi.")
+	}
+	h.publishCallbackState(ctx, e, status, agentID, outboundCallID)
+}
+
+func (h *CallHandler) publishCallbackState(ctx context.Context, e callqueue.Entry, status, agentID, outboundCallID string) {
+	s := &state.CallState{CallID: e.CallID, TraceID: e.CallID, TenantID: e.TenantID}
+	h.publishGenericEvent(ctx, constants.EventTypeCallbackStateChanged, s, map[string]interface{}{
+		"callbackId":     strings.TrimPrefix(e.ID, callbackEntryPrefix),
+		"tenantId":       e.TenantID,
+		"queueId":        e.QueueID,
+		"callbackUri":    e.FromURI,
+		"status":         status,
+		"attempts":       e.Attempts,
+		"agentId":        agentID,
+		"outboundCallId": outboundCallID,
+	})
+}
+
+// ProcessManualDial, ajanın kendi başlattığı giden aramayı B2BUA üzerinden açar.
+func (h *CallHandler) ProcessManualDial(ctx context.Context, req *agentv1.ProcessManualDialRequest) (*agentv1.ProcessManualDialResponse, error) {
+	if req.DestinationNumber == "" || req.UserId == "" || req.TenantId == "" {
+		return &agentv1.ProcessManualDialResponse{Accepted: false, ErrorMessage: "destination_number, user_id ve tenant_id zorunludur"}, nil
+	}
+
+	callID, err := h.originate(ctx, req.TenantId, req.UserId, req.DestinationNumber, &state.OutboundDial{AgentID: req.UserId})
+	if err != nil {
+		h.log.Warn().Str("event", "MANUAL_DIAL_FAILED").Str("agent_id", req.UserId).Err(err).Msg("Manuel arama başlatılamadı.")
+		return &agentv1.ProcessManualDialResponse{Accepted: false, ErrorMessage: err.Error()}, nil
+	}
+	return &agentv1.ProcessManualDialResponse{Accepted: true, CallId: callID}, nil
+}
+
+// originate, hedef numarayı arayan bir giden bacak açar. Bacak cevaplandığında ajan köprüyle bağlanır
+// (bkz. handleOutboundAnswered).
+func (h *CallHandler) originate(ctx context.Context, tenantID, agentID, destination string, ob *state.OutboundDial) (string, error) {
+	toURI := destination
+	if !strings.Contains(toURI, ":") {
+		toURI = "sip:" + toURI
+	}
+	agentURI := h.agentTargetURI(agentID)
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := h.clients.B2BUA.InitiateCall(reqCtx, &sipv1.InitiateCallRequest{
+		CallId:  newID("out-"),
+		FromUri: agentURI,
+		ToUri:   toURI,
+	})
+	if err == nil && (!resp.Success || resp.NewCallId == "") {
+		err = errors.New("B2BUA giden çağrıyı açamadı")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s := &state.CallState{
+		CallID:       resp.NewCallId,
+		TraceID:      resp.NewCallId,
+		TenantID:     tenantID,
+		LanguageCode: h.cfg.DefaultLanguageCode,
+		CurrentState: constants.StateBridging,
+		FromURI:      toURI,
+		ToURI:        agentURI,
+		Outbound:     ob,
+		CreatedAt:    time.Now(),
+	}
+	if err := h.stateManager.Set(ctx, s); err != nil {
+		h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Giden çağrı durumu kaydedilemedi.")
+	}
+	if err := database.CreateConversation(h.db, s.CallID, tenantID, "voice-outbound"); err != nil {
+		h.log.Warn().Str("event", "DB_CONVERSATION_CREATE_FAILED").Err(err).Msg("Konuşma kaydı veritabanına yazılamadı (Logic devam ediyor)")
+	}
+	return s.CallID, nil
+}
+
+// handleOutboundAnswered, giden bacağın cevaplanmasını yakalayıp ajanı köprüyle bağlar.
+// Olay bir giden aramaya ait değilse false döner.
+func (h *CallHandler) handleOutboundAnswered(ctx context.Context, callID string) bool {
+	var firstAnswer bool
+	s, err := h.stateManager.Update(ctx, callID, func(cs *state.CallState) error {
+		if cs.Outbound == nil {
+			return errNotOutbound
+		}
+		firstAnswer = !cs.Outbound.Answered
+		cs.Outbound.Answered = true
+		return nil
+	})
+	if err != nil || s == nil {
+		return false
+	}
+	if !firstAnswer {
+		return true
+	}
+
+	ob := s.Outbound
+	h.log.Info().Str("event", "OUTBOUND_ANSWERED").Str("call_id", callID).Str("agent_id", ob.AgentID).Msg("✅ Giden arama cevaplandı, ajan bağlanıyor.")
+	if ob.Callback != nil {
+		h.updateCallback(ctx, *ob.Callback, callbackStatusConnected, ob.AgentID, callID)
+	}
+	h.assignCallAgent(ctx, s, ob.AgentID)
+	h.handleBridgeCall(ctx, s, map[string]string{
+		"target_uri": h.agentTargetURI(ob.AgentID),
+		"fallback":   fallbackHangup,
+	})
+	return true
+}
+
+// endOutbound, giden arama kapandığında ajanı wrap-up'a alır; cevaplanmamış aramada ajanı serbest bırakıp geri aramayı yeniden dener.
+func (h *CallHandler) endOutbound(ctx context.Context, s *state.CallState) {
+	ob := s.Outbound
+	if ob == nil {
+		return
+	}
+	if ob.Answered {
+		h.startWrapUp(ctx, s.TenantID, ob.AgentID, s.CallID)
+		return
+	}
+	h.releaseAgent(ctx, s.TenantID, ob.AgentID)
+	if ob.Callback != nil {
+		h.retryCallback(ctx, *ob.Callback, ob.CallbackScore, callbackStatusNoAnswer)
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// callerFailureAnnouncement, başarısız bir kullanıcı turunu çalınacak anonsa eşler.
+// Tur başarısız değilse ok=false döner.
+func (h *CallHandler) callerFailureAnnouncement(sig pipelineSignal) (constants.AnnouncementID, bool) {
+	switch sig.Kind {
+	case signalKindNoInput:
+		return constants.AnnounceSystemCantHearYou, true
+	case signalKindLowConfidence:
+		return constants.AnnounceSystemCantUnderstand, true
+	case signalKindTranscript:
+		if isCallerSender(sig.Sender) && sig.Confidence != nil && *sig.Confidence < h.cfg.AgentMinSttConfidence {
+			return constants.AnnounceSystemCantUnderstand, true
+		}
+	}
+	return "", false
+}
+
+func isCallerSender(sender string) bool {
+	return transcriptSenders[strings.ToUpper(sender)] == "USER"
+}
+
+// trackCallerInput, sessizlik ve düşük güvenli STT sinyallerini çağrı bazlı sayar. Her başarısız turda
+// ilgili anonsu çalar; sayaç MaxCallerFailures'a ulaştığında veda anonslarını çalıp
+// çağrının sonlandırılması gerektiğini bildirir. Anlaşılır bir kullanıcı ifadesi sayacı sıfırlar.
+func (h *CallHandler) trackCallerInput(ctx context.Context, s *state.CallState, sig pipelineSignal) *pipelineFailure {
+	announcement, failed := h.callerFailureAnnouncement(sig)
+	if !failed {
+		if sig.Kind == signalKindTranscript && isCallerSender(sig.Sender) && s.CallerFailures > 0 {
+			s.CallerFailures = 0
+			h.saveCallerFailures(ctx, s)
+		}
+		return nil
+	}
+
+	s.CallerFailures++
+	h.saveCallerFailures(ctx, s)
+
+	l := h.log.With().Str("call_id", s.CallID).Int("caller_failures", s.CallerFailures).Logger()
+
+	limit := h.cfg.MaxCallerFailures
+	if limit < 1 {
+		limit = 1
+	}
+	if s.CallerFailures < limit {
+		l.Info().Str("event", "CALLER_INPUT_FAILED").Str("signal", sig.Kind).Msg("🔇 Kullanıcı girdisi alınamadı, yönlendirme anonsu çalınıyor.")
+		if err := h.playAnnouncement(ctx, s, announcement); err != nil {
+			l.Warn().Str("event", "ANNOUNCEMENT_PLAY_FAILED").Str("announcement_id", string(announcement)).Err(err).Msg("Anons çalınamadı.")
+		}
+		return nil
+	}
+
+	l.Warn().Str("event", "CALLER_MAX_FAILURES").Msg("⛔ Ardışık başarısız tur limiti aşıldı, çağrı kibarca sonlandırılıyor.")
+	h.playFarewell(ctx, s, constants.AnnounceSystemMaxFailures, constants.AnnounceSystemGoodbye)
+	return &pipelineFailure{
+		reason:   "MAX_CALLER_FAILURES",
+		graceful: true,
+		err:      errors.New("caller failure limit reached"),
+	}
+}
+
+func (h *CallHandler) saveCallerFailures(ctx context.Context, s *state.CallState) {
+	opCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	count := s.CallerFailures
+	_, err := h.stateManager.Update(opCtx, s.CallID, func(cs *state.CallState) error {
+		cs.CallerFailures = count
+		return nil
+	})
+	if err != nil && !errors.Is(err, state.ErrStateNotFound) {
+		h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Başarısız tur sayacı kaydedilemedi.")
+	}
+}
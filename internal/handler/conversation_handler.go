@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	agentv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/agent/v1"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+)
+
+// requireTenant, isteğin x-tenant-id metadata'sını döner; metadata yoksa veya boşsa istek reddedilir.
+func requireTenant(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get("x-tenant-id"); len(vals) > 0 && vals[0] != "" {
+		return vals[0], nil
+	}
+	return "", status.Error(codes.Unauthenticated, "x-tenant-id metadata zorunludur")
+}
+
+// recordingSenderType, transcript içinde ses kaydı girdilerini işaretler.
+const recordingSenderType = "SYSTEM"
+
+// HandleRecordingAvailable, medya katmanının ürettiği kaydı konuşmaya bağlayarak saklar.
+func (h *CallHandler) HandleRecordingAvailable(ctx context.Context, event *eventv1.CallRecordingAvailableEvent) {
+	l := h.log.With().Str("call_id", event.CallId).Logger()
+
+	if event.RecordingUri == "" {
+		l.Warn().Str("event", "RECORDING_URI_MISSING").Msg("call.recording.available olayında kayıt URI'si yok.")
+		return
+	}
+
+	bucket, uri := database.NormalizeRecordingURI(h.cfg.BucketName, event.RecordingUri)
+
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	rec := database.Recording{
+		Bucket:       bucket,
+		RecordingURI: uri,
+		PublicURL:    event.PublicUrl,
+	}
+	err := database.AddRecording(dbCtx, h.db, event.CallId, rec)
+	if errors.Is(err, database.ErrConversationNotFound) {
+		l.Warn().Str("event", "RECORDING_CONVERSATION_NOT_FOUND").Str("recording_uri", uri).Msg("Kayıt için konuşma bulunamadı.")
+		return
+	}
+	if err != nil {
+		l.Error().Str("event", "DB_RECORDING_SAVE_FAILED").Err(err).Msg("Ses kaydı veritabanına yazılamadı.")
+		return
+	}
+
+	l.Info().Str("event", "RECORDING_SAVED").Str("bucket", bucket).Str("recording_uri", uri).Msg("🎙️ Ses kaydı konuşmaya bağlandı.")
+	h.attachVoicemail(dbCtx, event.CallId, rec)
+}
+
+// GetConversationTranscript, konuşmanın metin dökümünü ve ses kayıtlarını tek bir zaman çizelgesinde döner.
+// Kayıtlar, QA ekranının sesi metnin yanında çalabilmesi için media_payload_json ile taşınır.
+func (h *CallHandler) GetConversationTranscript(ctx context.Context, req *agentv1.GetConversationTranscriptRequest) (*agentv1.GetConversationTranscriptResponse, error) {
+	if req.CallId == "" {
+		return nil, status.Error(codes.InvalidArgument, "call_id zorunludur")
+	}
+
+	// [ARCH-COMPLIANCE] Tenant Isolation: tenant'ı belirtilmeyen istek reddedilir; sorgu her zaman o tenant ile sınırlıdır.
+	tenantID, err := requireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	lines, err := database.GetTranscript(dbCtx, h.db, req.CallId, tenantID)
+	if err != nil {
+		h.log.Error().Str("event", "DB_TRANSCRIPT_READ_FAILED").Str("call_id", req.CallId).Err(err).Msg("Transcript okunamadı.")
+		return nil, status.Error(codes.Internal, "transcript okunamadı")
+	}
+	recordings, err := database.GetRecordings(dbCtx, h.db, req.CallId, tenantID)
+	if err != nil {
+		h.log.Error().Str("event", "DB_RECORDING_READ_FAILED").Str("call_id", req.CallId).Err(err).Msg("Kayıtlar okunamadı.")
+		return nil, status.Error(codes.Internal, "kayıtlar okunamadı")
+	}
+
+	entries := make([]*agentv1.TranscriptEntry, 0, len(lines)+len(recordings))
+	for _, t := range lines {
+		entries = append(entries, &agentv1.TranscriptEntry{
+			SenderType:  t.SenderType,
+			MessageText: t.MessageText,
+			CreatedAt:   timestamppb.New(t.CreatedAt),
+		})
+	}
+	for _, r := range recordings {
+		payload, _ := json.Marshal(map[string]string{
+			"type":         "recording",
+			"bucket":       r.Bucket,
+			"recordingUri": r.RecordingURI,
+			"publicUrl":    r.PublicURL,
+		})
+		entries = append(entries, &agentv1.TranscriptEntry{
+			SenderType:       recordingSenderType,
+			CreatedAt:        timestamppb.New(r.CreatedAt),
+			MediaPayloadJson: string(payload),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.AsTime().Before(entries[j].CreatedAt.AsTime())
+	})
+
+	return &agentv1.GetConversationTranscriptResponse{Entries: entries}, nil
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	mediav1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/media/v1"
+)
+
+// echoMinSamples, testin başarılı sayılması için gereken en az onay örneği sayısıdır.
+const echoMinSamples = 3
+
+// echoStats, media-service onay gecikmesi örneklerinden hesaplanan ortalama ve jitter değerleridir.
+// Arayanın cihazı sesi geri göndermediğinden trunk'ın gerçek gidiş-dönüş süresi bu testte ölçülemez.
+type echoStats struct {
+	samples     int
+	ackMs       float64
+	ackJitterMs float64
+	minAckMs    float64
+	maxAckMs    float64
+}
+
+// computeEchoStats, ortalama onay gecikmesini ve ardışık örnekler arasındaki ortalama mutlak farkı (jitter) hesaplar.
+func computeEchoStats(acks []float64) echoStats {
+	st := echoStats{samples: len(acks)}
+	if len(acks) == 0 {
+		return st
+	}
+	st.minAckMs, st.maxAckMs = math.Inf(1), math.Inf(-1)
+	var sum, diffSum float64
+	for i, v := range acks {
+		sum += v
+		st.minAckMs = math.Min(st.minAckMs, v)
+		st.maxAckMs = math.Max(st.maxAckMs, v)
+		if i > 0 {
+			diffSum += math.Abs(v - acks[i-1])
+		}
+	}
+	st.ackMs = sum / float64(len(acks))
+	if len(acks) > 1 {
+		st.ackJitterMs = diffSum / float64(len(acks)-1)
+	}
+	return st
+}
+
+// handleEchoTest, ECHO_TEST aksiyonunu yürütür: arayanın sesini media-service üzerinden kendisine geri çalar,
+// her ses parçası için media-service onayının gecikmesini ölçer ve sonucu echo_test_diagnostics tablosuna yazar.
+// Test, süresi dolunca veya çağrı bitince (call.ended) sona erer.
+func (h *CallHandler) handleEchoTest(s *state.CallState, actionData map[string]string) {
+	duration := time.Duration(h.cfg.EchoTestDurationSeconds) * time.Second
+	if v, err := strconv.Atoi(actionData["echo_duration_seconds"]); err == nil && v > 0 {
+		duration = time.Duration(v) * time.Second
+	}
+	maxLatency := h.cfg.EchoTestMaxLatencyMs
+	if v, err := strconv.ParseFloat(actionData["echo_max_latency_ms"], 64); err == nil && v > 0 {
+		maxLatency = v
+	}
+
+	callCtx, release := h.callScopes.begin(context.Background(), s.CallID)
+	go func() {
+		defer release()
+
+		started := time.Now()
+		acks, failure := h.runEchoLoopback(callCtx, s, duration)
+		if errors.Is(callCtx.Err(), context.Canceled) {
+			h.log.Info().Str("event", "ECHO_TEST_ABORTED").Str("call_id", s.CallID).Msg("Arayan kapattı, echo testi yarıda kaldı.")
+			failure = "CALLER_HANGUP"
+		}
+
+		st := computeEchoStats(acks)
+		if failure == "" {
+			switch {
+			case st.samples < echoMinSamples:
+				failure = "INSUFFICIENT_SAMPLES"
+			case st.ackMs > maxLatency:
+				failure = "ACK_LATENCY_TOO_HIGH"
+			}
+		}
+		h.saveEchoDiagnostic(s, st, failure, started)
+		if failure != "CALLER_HANGUP" {
+			h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
+		}
+	}()
+}
+
+// runEchoLoopback, MediaService.RecordAudio ile arayanın sesini okur ve her parçayı StreamAudioToCall ile
+// aynı çağrıya geri basar. Ölçülen süre, parçanın okunmasından media-service'in o parça için döndüğü
+// StreamAudioToCallResponse'a (onay) kadar geçen süredir (yanıtlar parçalarla aynı sırada gelir); sesin arayana
+// ulaşıp geri gelmesini kapsamaz. Süre dolması
+// normal bitiştir; akışlar açılamaz veya koparsa failure nedeni döner.
+func (h *CallHandler) runEchoLoopback(callCtx context.Context, s *state.CallState, duration time.Duration) ([]float64, string) {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+
+	if s.ServerRtpPort == 0 {
+		l.Warn().Str("event", "ECHO_TEST_MEDIA_MISSING").Msg("Echo testi için RTP bilgisi yok.")
+		return nil, "MEDIA_INFO_MISSING"
+	}
+
+	ctx, cancel := context.WithTimeout(callCtx, duration)
+	defer cancel()
+	if s.TenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", s.TenantID)
+	}
+	if s.TraceID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-trace-id", s.TraceID)
+	}
+
+	record, err := h.clients.Media.RecordAudio(ctx, &mediav1.RecordAudioRequest{ServerRtpPort: s.ServerRtpPort})
+	if err != nil {
+		l.Error().Str("event", "ECHO_TEST_START_FAILED").Err(err).Msg("❌ Arayanın sesi okunamıyor.")
+		return nil, "MEDIA_UNREACHABLE"
+	}
+	playback, err := h.clients.Media.StreamAudioToCall(ctx)
+	if err != nil {
+		l.Error().Str("event", "ECHO_TEST_START_FAILED").Err(err).Msg("❌ Ses arayana geri basılamıyor.")
+		return nil, "MEDIA_UNREACHABLE"
+	}
+	l.Info().Str("event", "ECHO_TEST_STARTED").Uint32("server_rtp_port", s.ServerRtpPort).Dur("duration", duration).Msg("🔊 Echo testi başladı.")
+
+	// Okuma tarafı her parçanın okunduğu anı sıraya koyar; yanıt tarafı sıradaki ilk anı kendi yanıtıyla eşler.
+	sent := make(chan time.Time, 256)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(sent)
+		defer func() { _ = playback.CloseSend() }()
+		for {
+			chunk, err := record.Recv()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			at := time.Now()
+			if err := playback.Send(&mediav1.StreamAudioToCallRequest{CallId: s.CallID, AudioChunk: chunk.AudioData}); err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case sent <- at:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	var acks []float64
+	for {
+		resp, err := playback.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return acks, ""
+		}
+		if err != nil {
+			l.Warn().Str("event", "ECHO_TEST_STREAM_FAILED").Err(err).Msg("Echo akışı koptu.")
+			return acks, "MEDIA_STREAM_BROKEN"
+		}
+		at, ok := <-sent
+		if !ok {
+			if err := <-readErr; err != nil && err != io.EOF && ctx.Err() == nil {
+				l.Warn().Str("event", "ECHO_TEST_STREAM_FAILED").Err(err).Msg("Arayanın sesi okunamadı.")
+				return acks, "MEDIA_STREAM_BROKEN"
+			}
+			return acks, ""
+		}
+		if !resp.Success {
+			l.Warn().Str("event", "ECHO_TEST_PLAYBACK_ERROR").Str("message", resp.ErrorMessage).Msg("Media-service sesi geri basamadı.")
+			return acks, "PLAYBACK_ERROR"
+		}
+		acks = append(acks, float64(time.Since(at).Microseconds())/1000)
+	}
+}
+
+func (h *CallHandler) saveEchoDiagnostic(s *state.CallState, st echoStats, failure string, started time.Time) {
+	d := database.EchoDiagnostic{
+		CallID:           s.CallID,
+		TenantID:         s.TenantID,
+		CallerRtpAddr:    s.CallerRtpAddr,
+		ServerRtpPort:    s.ServerRtpPort,
+		Samples:          st.samples,
+		MediaAckMs:       st.ackMs,
+		MediaAckJitterMs: st.ackJitterMs,
+		MinMediaAckMs:    st.minAckMs,
+		MaxMediaAckMs:    st.maxAckMs,
+		Success:          failure == "",
+		FailureReason:    failure,
+		StartedAt:        started,
+		FinishedAt:       time.Now(),
+	}
+
+	h.log.Info().Str("event", "ECHO_TEST_FINISHED").
+		Str("call_id", s.CallID).
+		Bool("success", d.Success).
+		Str("failure_reason", failure).
+		Int("samples", st.samples).
+		Float64("media_ack_ms", st.ackMs).
+		Float64("media_ack_jitter_ms", st.ackJitterMs).
+		Msg("🔊 Echo testi tamamlandı.")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := database.InsertEchoDiagnostic(ctx, h.db, d); err != nil {
+		h.log.Warn().Str("event", "ECHO_TEST_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Echo testi sonucu kaydedilemedi.")
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeEchoStats(t *testing.T) {
+	tests := []struct {
+		name string
+		acks []float64
+		want echoStats
+	}{
+		{
+			name: "no samples",
+			acks: nil,
+			want: echoStats{},
+		},
+		{
+			name: "single sample has no jitter",
+			acks: []float64{42},
+			want: echoStats{samples: 1, ackMs: 42, minAckMs: 42, maxAckMs: 42},
+		},
+		{
+			name: "constant ack latency",
+			acks: []float64{20, 20, 20},
+			want: echoStats{samples: 3, ackMs: 20, minAckMs: 20, maxAckMs: 20},
+		},
+		{
+			name: "jitter is mean absolute difference of consecutive samples",
+			acks: []float64{10, 30, 20, 40},
+			want: echoStats{samples: 4, ackMs: 25, ackJitterMs: (20 + 10 + 20) / 3.0, minAckMs: 10, maxAckMs: 40},
+		},
+		{
+			name: "order matters for jitter but not for latency",
+			acks: []float64{10, 20, 30, 40},
+			want: echoStats{samples: 4, ackMs: 25, ackJitterMs: 10, minAckMs: 10, maxAckMs: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeEchoStats(tt.acks)
+			if got.samples != tt.want.samples {
+				t.Fatalf("samples = %d, want %d", got.samples, tt.want.samples)
+			}
+			for _, f := range []struct {
+				field     string
+				got, want float64
+			}{
+				{"ackMs", got.ackMs, tt.want.ackMs},
+				{"ackJitterMs", got.ackJitterMs, tt.want.ackJitterMs},
+				{"minAckMs", got.minAckMs, tt.want.minAckMs},
+				{"maxAckMs", got.maxAckMs, tt.want.maxAckMs},
+			} {
+				if math.Abs(f.got-f.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", f.field, f.got, f.want)
+				}
+			}
+		})
+	}
+}
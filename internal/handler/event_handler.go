@@ -2,13 +2,23 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/sentiric/sentiric-agent-service/internal/constants"
 	"github.com/sentiric/sentiric-agent-service/internal/ctxlogger"
+	"github.com/sentiric/sentiric-agent-service/internal/queue"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
 	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
 )
 
@@ -16,76 +26,209 @@ type EventHandler struct {
 	log             zerolog.Logger
 	eventsProcessed *prometheus.CounterVec
 	eventsFailed    *prometheus.CounterVec
+	eventsUnrouted  *prometheus.CounterVec
+	eventsDuplicate *prometheus.CounterVec
+	dedup           *state.DedupStore
 	callHandler     *CallHandler
+	registry        *EventRegistry
 }
 
-func NewEventHandler(log zerolog.Logger, processed, failed *prometheus.CounterVec, callHandler *CallHandler) *EventHandler {
-	return &EventHandler{
+func NewEventHandler(log zerolog.Logger, processed, failed, unrouted, duplicate *prometheus.CounterVec, dedup *state.DedupStore, callHandler *CallHandler) *EventHandler {
+	h := &EventHandler{
 		log:             log,
 		eventsProcessed: processed,
 		eventsFailed:    failed,
+		eventsUnrouted:  unrouted,
+		eventsDuplicate: duplicate,
+		dedup:           dedup,
 		callHandler:     callHandler,
 	}
+	h.registry = h.buildRegistry()
+	return h
 }
 
-func (h *EventHandler) HandleRabbitMQMessage(body []byte) {
-	// 1. CallStartedEvent
-	var startedEvent eventv1.CallStartedEvent
-	if err := proto.Unmarshal(body, &startedEvent); err == nil && startedEvent.EventType == string(constants.EventTypeCallStarted) {
-		h.processCallStarted(&startedEvent)
-		return
+func (h *EventHandler) buildRegistry() *EventRegistry {
+	r := NewEventRegistry()
+
+	Register(r, constants.EventTypeCallStarted,
+		func() *eventv1.CallStartedEvent { return &eventv1.CallStartedEvent{} },
+		h.callHandler.HandleCallStarted)
+
+	Register(r, constants.EventTypeCallEnded,
+		func() *eventv1.CallEndedEvent { return &eventv1.CallEndedEvent{} },
+		func(ctx context.Context, e *eventv1.CallEndedEvent) {
+			h.callHandler.HandleCallEnded(ctx, e.CallId, e.Reason)
+		})
+
+	Register(r, constants.EventTypeUserIdentifiedForCall,
+		func() *eventv1.UserIdentifiedForCallEvent { return &eventv1.UserIdentifiedForCallEvent{} },
+		h.callHandler.HandleUserIdentified)
+
+	Register(r, constants.EventTypeCallRecordingAvailable,
+		func() *eventv1.CallRecordingAvailableEvent { return &eventv1.CallRecordingAvailableEvent{} },
+		h.callHandler.HandleRecordingAvailable)
+
+	Register(r, constants.EventTypeCallMediaPlaybackFinished,
+		func() *eventv1.GenericEvent { return &eventv1.GenericEvent{} },
+		h.callHandler.HandlePlaybackFinished)
+
+	for _, t := range []constants.EventType{
+		constants.EventTypeTransferRequested,
+		constants.EventTypeTransferComplete,
+		constants.EventTypeTransferCancel,
+		constants.EventTypeTransferDeclined,
+	} {
+		Register(r, t,
+			func() *eventv1.GenericEvent { return &eventv1.GenericEvent{} },
+			h.callHandler.HandleTransferCommand)
 	}
 
-	// 2. CallEndedEvent
-	var endedEvent eventv1.CallEndedEvent
-	if err := proto.Unmarshal(body, &endedEvent); err == nil && endedEvent.EventType == string(constants.EventTypeCallEnded) {
-		h.processCallEnded(&endedEvent)
-		return
+	Register(r, constants.EventTypeAgentPresenceChanged,
+		func() *eventv1.GenericEvent { return &eventv1.GenericEvent{} },
+		h.callHandler.HandlePresenceChanged)
+
+	Register(r, constants.EventTypeCallbackRequested,
+		func() *eventv1.GenericEvent { return &eventv1.GenericEvent{} },
+		h.callHandler.HandleCallbackRequested)
+
+	// Servisin kendi yayınladığı olaylar.
+	r.Ignore(
+		constants.EventTypeCallDialogStateChanged,
+		constants.EventTypeTransferStateChanged,
+		constants.EventTypeQueueEntered,
+		constants.EventTypeCallbackStateChanged,
+		constants.EventTypeVoicemailCreated,
+		constants.EventTypeWrapUpStarted,
+		constants.EventTypeWrapUpCompleted,
+		constants.EventTypeOfferMissed,
+	)
+
+	// Agent'ın ilgilenmediği ama aynı exchange'den gelen olaylar: sayılır ve yutulur.
+	Register[*eventv1.GenericEvent](r, constants.EventTypeCallTerminateRequest,
+		func() *eventv1.GenericEvent { return &eventv1.GenericEvent{} }, nil)
+
+	return r
+}
+
+const (
+	contentTypeJSON     = "application/json"
+	contentTypeProtobuf = "application/protobuf"
+)
+
+var jsonUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// decodeEvent, gövdeyi Content-Type'a göre aynı kontrat mesajına çözer.
+// Content-Type belirtilmemiş mesajlar geriye dönük uyumluluk için Protobuf kabul edilir.
+func decodeEvent(contentType string, body []byte, msg proto.Message) error {
+	mediaType := contentTypeProtobuf
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return queue.Reject("unsupported_content_type", fmt.Errorf("content-type çözümlenemedi %q: %w", contentType, err))
+		}
+		mediaType = parsed
 	}
 
-	// 3. Media Olayları (Zararsızca Yoksay)
-	// [YENİ]: GenericEvent (Protobuf) kontrolü. Workflow'dan gelen "call.terminate.request" gibi olayları güvenle yut.
-	var genericEvent eventv1.GenericEvent
-	if err := proto.Unmarshal(body, &genericEvent); err == nil && genericEvent.EventType != "" {
-		if genericEvent.EventType == "call.recording.available" ||
-			genericEvent.EventType == "call.media.playback.finished" ||
-			genericEvent.EventType == "call.terminate.request" {
-			h.eventsProcessed.WithLabelValues(genericEvent.EventType).Inc()
-			return
+	switch mediaType {
+	case contentTypeJSON:
+		if err := jsonUnmarshal.Unmarshal(body, msg); err != nil {
+			return queue.Reject("json_decode_error", err)
+		}
+	case contentTypeProtobuf, "application/x-protobuf":
+		if err := proto.Unmarshal(body, msg); err != nil {
+			return queue.Reject("protobuf_decode_error", err)
 		}
-		h.eventsProcessed.WithLabelValues(genericEvent.EventType).Inc()
+	default:
+		return queue.Reject("unsupported_content_type", fmt.Errorf("desteklenmeyen content-type: %s", contentType))
+	}
+	return nil
+}
+
+func (h *EventHandler) HandleRabbitMQMessage(d queue.Delivery) error {
+	eventType := d.EventType()
+	if h.registry.isIgnored(eventType) {
+		return nil
+	}
+
+	route, ok := h.registry.lookup(eventType)
+	if !ok {
 		// [ARCH-COMPLIANCE] ARCH-007
-		h.log.Debug().Str("event", "EVENT_IGNORED_GENERIC").Str("type", genericEvent.EventType).Msg("GenericEvent alındı (No-op).")
-		return
+		h.log.Debug().Str("event", "EVENT_UNROUTED").Str("type", eventType).Msg("Kayıtlı işleyicisi olmayan olay yoksayıldı.")
+		h.eventsUnrouted.WithLabelValues(eventType).Inc()
+		return nil
 	}
 
-	// [YENİ]: Native struct kontrolü (Panopticon log kirliliğini önler)
-	var recEvent eventv1.CallRecordingAvailableEvent
-	if err := proto.Unmarshal(body, &recEvent); err == nil && recEvent.EventType == "call.recording.available" {
-		h.eventsProcessed.WithLabelValues(recEvent.EventType).Inc()
-		return
+	msg := route.newMessage()
+	if err := decodeEvent(d.ContentType, d.Body, msg); err != nil {
+		var rejectErr *queue.RejectError
+		reason := "unmarshal_error"
+		if errors.As(err, &rejectErr) {
+			reason = rejectErr.Reason
+		}
+		h.log.Warn().Str("event", "EVENT_DECODE_FAILED").Str("type", eventType).Str("content_type", d.ContentType).Err(err).Msg("Olay gövdesi çözümlenemedi.")
+		h.eventsFailed.WithLabelValues(eventType, reason).Inc()
+		return err
 	}
 
-	// YENİ: Playback bitiş eventini yut
-	var playEvent eventv1.GenericEvent
-	if err := proto.Unmarshal(body, &playEvent); err == nil && playEvent.EventType == "call.media.playback.finished" {
-		return
+	l := h.log.With().Str("event_type", eventType).Logger()
+
+	key := idempotencyKey(eventType, d, msg)
+	dedupCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	seen, err := h.dedup.Seen(dedupCtx, key)
+	cancel()
+	if err != nil {
+		// Redis erişilemezse olay kaybetmek yerine işlemeyi tercih ediyoruz (fail-open).
+		l.Warn().Str("event", "EVENT_DEDUP_UNAVAILABLE").Err(err).Msg("Idempotency kontrolü yapılamadı, olay işleniyor.")
+	} else if seen {
+		l.Debug().Str("event", "DUPLICATE_EVENT_IGNORED").Str("idempotency_key", key).Msg("Duplicate event ignored.")
+		h.eventsDuplicate.WithLabelValues(eventType).Inc()
+		return nil
 	}
 
-	// Buraya gelirse gerçekten bozuk bir eventtir.
-	// Ancak log level'ı DEBUG yapıyoruz, ERROR veya WARN olmasın ki SRE dashboard'u kirletmesin.
-	// [ARCH-COMPLIANCE] ARCH-007
-	h.log.Debug().Str("event", "EVENT_UNRECOGNIZED").Msg("Unrecognized event structure received in Agent.")
-	h.eventsFailed.WithLabelValues("unknown", "unmarshal_error").Inc()
-}
+	h.eventsProcessed.WithLabelValues(eventType).Inc()
+	if route.handle != nil {
+		ctx := ctxlogger.ToContext(context.Background(), l)
+		route.handle(ctx, msg)
+	}
 
-func (h *EventHandler) processCallStarted(event *eventv1.CallStartedEvent) {
-	h.eventsProcessed.WithLabelValues(event.EventType).Inc()
-	ctx := ctxlogger.ToContext(context.Background(), h.log)
-	h.callHandler.HandleCallStarted(ctx, event)
+	// Yalnızca tamamlanan olaylar işaretlenir; işleyici panik yaparsa buraya gelinmez.
+	markCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := h.dedup.MarkProcessed(markCtx, key); err != nil {
+		l.Warn().Str("event", "EVENT_DEDUP_MARK_FAILED").Str("idempotency_key", key).Err(err).Msg("Olay işlenmiş olarak işaretlenemedi.")
+	}
+	return nil
 }
 
-func (h *EventHandler) processCallEnded(event *eventv1.CallEndedEvent) {
-	h.eventsProcessed.WithLabelValues(event.EventType).Inc()
-	h.callHandler.HandleCallEnded(context.Background(), event.CallId)
+// idempotencyKey, yeniden teslimatları tanımak için olaydan kararlı bir anahtar türetir.
+// Öncelik AMQP message-id'dedir; yoksa olay türü + çağrı (veya trace) kimliği + zaman
+// damgası kullanılır. Bunlar da yoksa gövdenin özeti alınır.
+func idempotencyKey(eventType string, d queue.Delivery, msg proto.Message) string {
+	if d.MessageID != "" {
+		return "msg:" + d.MessageID
+	}
+
+	var subject string
+	if m, ok := msg.(interface{ GetCallId() string }); ok {
+		subject = m.GetCallId()
+	}
+	if subject == "" {
+		if m, ok := msg.(interface{ GetTraceId() string }); ok {
+			subject = m.GetTraceId()
+		}
+	}
+
+	var ts *timestamppb.Timestamp
+	if m, ok := msg.(interface {
+		GetTimestamp() *timestamppb.Timestamp
+	}); ok {
+		ts = m.GetTimestamp()
+	}
+
+	if subject != "" && ts != nil {
+		return fmt.Sprintf("%s:%s:%d", eventType, subject, ts.AsTime().UnixNano())
+	}
+
+	sum := sha256.Sum256(d.Body)
+	return eventType + ":body:" + hex.EncodeToString(sum[:])
 }
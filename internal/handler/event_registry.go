@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+)
+
+// eventRoute, bir olay türünün hangi kontrat mesajına çözüleceğini ve
+// çözülen mesajın kime teslim edileceğini tanımlar.
+type eventRoute struct {
+	newMessage func() proto.Message
+	handle     func(ctx context.Context, msg proto.Message)
+}
+
+// EventRegistry, olay türü -> (decoder, handler) eşlemesini tutar.
+// Deneme-yanılma ile unmarshal yerine routing key / 'type' başlığı üzerinden yönlendirme yapılır.
+type EventRegistry struct {
+	routes  map[constants.EventType]eventRoute
+	ignored map[constants.EventType]bool
+}
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{routes: make(map[constants.EventType]eventRoute), ignored: make(map[constants.EventType]bool)}
+}
+
+// Ignore, servisin kendi yayınladığı olay türlerini işaretler. Kuyruk exchange'e "#" ile bağlı olduğundan
+// bu olaylar bize geri döner; çözülmeden, sayılmadan ve idempotency anahtarı yazılmadan atlanırlar.
+func (r *EventRegistry) Ignore(eventTypes ...constants.EventType) {
+	for _, t := range eventTypes {
+		r.ignored[t] = true
+	}
+}
+
+func (r *EventRegistry) isIgnored(eventType string) bool {
+	return r.ignored[constants.EventType(eventType)]
+}
+
+// Register, bir olay türü için tipli bir işleyici kaydeder.
+// handle nil verilirse olay yalnızca sayılır ve yutulur.
+func Register[T proto.Message](r *EventRegistry, eventType constants.EventType, newMessage func() T, handle func(context.Context, T)) {
+	route := eventRoute{
+		newMessage: func() proto.Message { return newMessage() },
+	}
+	if handle != nil {
+		route.handle = func(ctx context.Context, msg proto.Message) {
+			handle(ctx, msg.(T))
+		}
+	}
+	r.routes[eventType] = route
+}
+
+func (r *EventRegistry) lookup(eventType string) (eventRoute, bool) {
+	route, ok := r.routes[constants.EventType(eventType)]
+	return route, ok
+}
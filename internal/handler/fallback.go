@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/sentiric/sentiric-agent-service/internal/queuestats"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// Dialplan'ın action_data ile seçebileceği fallback aksiyonları.
+const (
+	fallbackQueue        = "queue"
+	fallbackAnnouncement = "announcement"
+	fallbackVoicemail    = "voicemail"
+	fallbackCallback     = "callback"
+	fallbackAI           = "ai"
+	fallbackHangup       = "hangup"
+)
+
+// fallbackAction, bir sonuca özel fallback'i (örn. busy_fallback, no_answer_fallback) arar;
+// yoksa genel fallback'e, o da yoksa çağrıyı kapatmaya düşer.
+func fallbackAction(actionData map[string]string, outcome string) string {
+	if v := actionData[outcome+"_fallback"]; v != "" {
+		return v
+	}
+	if v := actionData["fallback"]; v != "" {
+		return v
+	}
+	return fallbackHangup
+}
+
+// runFallback, birincil akış (köprü, kuyruk vb.) sonuç vermediğinde dialplan'da tanımlı alternatifi uygular.
+func (h *CallHandler) runFallback(ctx context.Context, s *state.CallState, actionData map[string]string, outcome string) {
+	l := h.log.With().Str("call_id", s.CallID).Str("outcome", outcome).Logger()
+	action := fallbackAction(actionData, outcome)
+	l.Info().Str("event", "FALLBACK_APPLIED").Str("fallback", action).Msg("↪️ Fallback uygulanıyor.")
+
+	switch action {
+	case fallbackQueue:
+		h.handleEnqueueCall(ctx, s, actionData)
+	case fallbackAnnouncement:
+		if actionData["fallback_announcement_id"] == "" {
+			l.Warn().Str("event", "FALLBACK_ANNOUNCEMENT_MISSING").Msg("Fallback anonsu tanımlı değil, çağrı sonlandırılıyor.")
+			h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
+			return
+		}
+		h.handlePlayStaticAnnouncement(s, map[string]string{
+			"announcement_id": actionData["fallback_announcement_id"],
+			"on_finish":       actionData["fallback_on_finish"],
+		})
+	case fallbackVoicemail:
+		h.handleVoicemail(s, actionData)
+	case fallbackCallback:
+		if err := h.scheduleCallback(ctx, s, "", actionData, queuestats.ExitCallback); err != nil {
+			l.Warn().Str("event", "CALLBACK_UNAVAILABLE").Err(err).Msg("Geri arama alınamadı, çağrı sonlandırılıyor.")
+			h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
+		}
+	case fallbackAI:
+		h.runTASPipeline(ctx, s, actionData)
+	default:
+		h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
+	}
+}
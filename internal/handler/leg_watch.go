@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// legWatches, çalan bacakların (köprü B-leg'i, danışma bacağı) zaman aşımı izleyicilerini tutar.
+// Bacak cevaplanınca, kapanınca veya çağrı bitince izleyici iptal edilir; böylece cevaplanmış bir bacak için
+// goroutine zaman aşımına kadar beklemez. Olay başka bir replikaya düşerse buradaki izleyici çalışmaya devam eder;
+// sonucun tek kez yazılması zaman aşımı işleyicisindeki atomik durum güncellemesiyle sağlanır.
+type legWatches struct {
+	mu      sync.Mutex
+	watches map[string]*legWatch
+}
+
+type legWatch struct {
+	cancel context.CancelFunc
+}
+
+// start, legID için timeout sonunda onTimeout'u çalıştıran bir izleyici başlatır. Aynı bacak için önceki izleyici iptal edilir.
+func (w *legWatches) start(legID string, timeout time.Duration, onTimeout func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	watch := &legWatch{cancel: cancel}
+
+	w.mu.Lock()
+	if w.watches == nil {
+		w.watches = make(map[string]*legWatch)
+	}
+	if prev, ok := w.watches[legID]; ok {
+		prev.cancel()
+	}
+	w.watches[legID] = watch
+	w.mu.Unlock()
+
+	go func() {
+		defer w.release(legID, watch)
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			onTimeout()
+		}
+	}()
+}
+
+// stop, bacağın izleyicisini iptal eder; izleyici yoksa (ör. başka replikada) bir şey yapmaz.
+func (w *legWatches) stop(legID string) {
+	w.mu.Lock()
+	watch, ok := w.watches[legID]
+	delete(w.watches, legID)
+	w.mu.Unlock()
+	if ok {
+		watch.cancel()
+	}
+}
+
+// release, biten izleyiciyi, yerine yenisi konmadıysa kayıttan siler.
+func (w *legWatches) release(legID string, watch *legWatch) {
+	watch.cancel()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.watches[legID] == watch {
+		delete(w.watches, legID)
+	}
+}
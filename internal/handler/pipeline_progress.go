@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/metrics"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+const (
+	signalKindPhase         = "phase"
+	signalKindTranscript    = "transcript"
+	signalKindNoInput       = "no_input"
+	signalKindLowConfidence = "low_confidence"
+)
+
+// pipelineSignal, RunPipelineResponse.message alanından çözülen yapılandırılmış sinyaldir.
+// TAS bu alana ya düz metin (örn. "THINKING") ya da {"kind": "...", ...} biçiminde JSON yazar.
+type pipelineSignal struct {
+	Kind       string   `json:"kind"`
+	Phase      string   `json:"phase,omitempty"`
+	Sender     string   `json:"sender,omitempty"`
+	Text       string   `json:"text,omitempty"`
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// Hata sinyalleri: hangi bileşenin (stt/tts) arızalandığı ve yeniden denemenin anlamlı olup olmadığı.
+	Component string `json:"component,omitempty"`
+	Retryable *bool  `json:"retryable,omitempty"`
+}
+
+var dialogPhases = map[string]constants.DialogState{
+	string(constants.StateListening): constants.StateListening,
+	string(constants.StateThinking):  constants.StateThinking,
+	string(constants.StateSpeaking):  constants.StateSpeaking,
+}
+
+func parsePipelineSignal(message string) pipelineSignal {
+	message = strings.TrimSpace(message)
+	if strings.HasPrefix(message, "{") {
+		var sig pipelineSignal
+		if err := json.Unmarshal([]byte(message), &sig); err == nil {
+			return sig
+		}
+	}
+	if _, ok := dialogPhases[strings.ToUpper(message)]; ok {
+		return pipelineSignal{Kind: signalKindPhase, Phase: strings.ToUpper(message)}
+	}
+	return pipelineSignal{}
+}
+
+// dialogStateFor, pipeline yanıtını diyalog durumuna eşler. Faz bilgisi taşımayan
+// RUNNING yanıtları yalnızca karşılamadan dinlemeye geçişi işaretler.
+func dialogStateFor(resp *telephonyv1.RunPipelineResponse, sig pipelineSignal, current constants.DialogState) (constants.DialogState, bool) {
+	switch resp.State {
+	case telephonyv1.RunPipelineResponse_STATE_STARTING:
+		return constants.StateWelcoming, true
+	case telephonyv1.RunPipelineResponse_STATE_RUNNING:
+		if phase, ok := dialogPhases[strings.ToUpper(sig.Phase)]; ok {
+			return phase, true
+		}
+		if current == constants.StateWelcoming {
+			return constants.StateListening, true
+		}
+	case telephonyv1.RunPipelineResponse_STATE_STOPPED:
+		return constants.StateEnded, true
+	}
+	return "", false
+}
+
+// phaseTracker, bir pipeline oturumundaki mevcut fazı ve faza giriş zamanını tutar.
+type phaseTracker struct {
+	current constants.DialogState
+	since   time.Time
+}
+
+func newPhaseTracker(initial constants.DialogState) *phaseTracker {
+	return &phaseTracker{current: initial, since: time.Now()}
+}
+
+// trackPipelinePhase, faz değiştiyse süreyi histogram'a yazar, CallState'i günceller
+// ve süpervizör ekranları için durum değişikliği olayını yayınlar.
+func (h *CallHandler) trackPipelinePhase(s *state.CallState, t *phaseTracker, resp *telephonyv1.RunPipelineResponse, sig pipelineSignal) {
+	next, ok := dialogStateFor(resp, sig, t.current)
+	if !ok || next == t.current {
+		return
+	}
+
+	now := time.Now()
+	previous := t.current
+	if previous != "" {
+		metrics.DialogPhaseDuration.WithLabelValues(string(previous)).Observe(now.Sub(t.since).Seconds())
+	}
+	t.current, t.since = next, now
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		cs.CurrentState = next
+		cs.StateChangedAt = now
+		return nil
+	})
+	if err != nil && !errors.Is(err, state.ErrStateNotFound) {
+		h.log.Warn().Str("event", "DIALOG_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Diyalog durumu kaydedilemedi.")
+	}
+
+	h.log.Debug().Str("event", "DIALOG_STATE_CHANGED").
+		Str("call_id", s.CallID).
+		Str("from", string(previous)).
+		Str("to", string(next)).
+		Msg("Diyalog fazı değişti.")
+
+	h.publishGenericEvent(ctx, constants.EventTypeCallDialogStateChanged, s, map[string]interface{}{
+		"callId":        s.CallID,
+		"tenantId":      s.TenantID,
+		"state":         next,
+		"previousState": previous,
+		"changedAt":     now.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// publishGenericEvent, çağrı bağlamındaki bir olayı GenericEvent olarak sentiric_events exchange'ine yayınlar.
+func (h *CallHandler) publishGenericEvent(ctx context.Context, eventType constants.EventType, s *state.CallState, payload interface{}) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		h.log.Error().Str("event", "EVENT_PAYLOAD_MARSHAL_FAIL").Str("type", string(eventType)).Err(err).Msg("Olay yükü JSON'a çevrilemedi.")
+		return
+	}
+
+	body, err := proto.Marshal(&eventv1.GenericEvent{
+		EventType:   string(eventType),
+		TraceId:     s.TraceID,
+		Timestamp:   timestamppb.Now(),
+		TenantId:    s.TenantID,
+		PayloadJson: string(payloadJSON),
+	})
+	if err != nil {
+		h.log.Error().Str("event", "PROTO_MARSHAL_FAIL").Str("type", string(eventType)).Err(err).Msg("Olay Protobuf'a çevrilemedi.")
+		return
+	}
+
+	if err := h.publisher.PublishProtobuf(ctx, string(eventType), body); err != nil {
+		h.log.Warn().Str("event", "EVENT_PUBLISH_FAIL").Str("type", string(eventType)).Err(err).Msg("Olay yayınlanamadı.")
+	}
+}
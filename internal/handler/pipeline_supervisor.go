@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/metrics"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+// modelQuarantine, arızalanan bir modelin yeni seçimlerden dışlanacağı süredir.
+const modelQuarantine = 2 * time.Minute
+
+// Pipeline yeniden başlatma beklemesi: her ardışık arızada iki katına çıkar, üst sınırı aşmaz.
+const (
+	restartBackoffBase = 500 * time.Millisecond
+	restartBackoffMax  = 8 * time.Second
+)
+
+// restartBackoff, n'inci ardışık arızadan sonra beklenecek süredir. Sürenin yarısı sabit, yarısı rastgeledir
+// (jitter); böylece aynı anda düşen çağrılar TAS'ı aynı anda yeniden denemez.
+func restartBackoff(n int) time.Duration {
+	d := restartBackoffMax
+	if n < 1 {
+		n = 1
+	}
+	if n <= 5 {
+		d = min(restartBackoffBase<<(n-1), restartBackoffMax)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// pipelineFailure, pipeline akışının neden sonlandığını ve yeniden denenip denenemeyeceğini tanımlar.
+type pipelineFailure struct {
+	reason    string
+	component string
+	retryable bool
+	// graceful, çağrının bir arıza değil planlı bir akış sonucu kapatıldığını belirtir.
+	graceful bool
+	err      error
+}
+
+// classifyStreamError, gRPC akış hatasını sınıflandırır. Geçici altyapı hataları yeniden denenir;
+// iptal veya istemci kaynaklı hatalar denenmez.
+func classifyStreamError(err error) *pipelineFailure {
+	f := &pipelineFailure{reason: "PIPELINE_BROKEN", err: err}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		f.retryable = true
+	}
+	return f
+}
+
+// classifyStateError, STATE_ERROR yanıtını sınıflandırır. Yapılandırılmış sinyal yoksa
+// arızalı bileşen mesaj metninden tahmin edilir ve hata yeniden denenebilir kabul edilir.
+func classifyStateError(resp *telephonyv1.RunPipelineResponse, sig pipelineSignal) *pipelineFailure {
+	f := &pipelineFailure{
+		reason:    "PIPELINE_ERROR",
+		component: strings.ToLower(sig.Component),
+		retryable: sig.Retryable == nil || *sig.Retryable,
+		err:       errors.New(resp.Message),
+	}
+	if f.component == "" {
+		msg := strings.ToLower(resp.Message)
+		switch {
+		case strings.Contains(msg, "tts"):
+			f.component = "tts"
+		case strings.Contains(msg, "stt"):
+			f.component = "stt"
+		}
+	}
+	return f
+}
+
+// supervisePipeline, pipeline akışını sonuna kadar tüketir. Yeniden denenebilir arızalarda yedek
+// modellerle akışı yeniden başlatır; ardışık arıza bütçesi (PipelineMaxRestarts) dolunca çağrıyı sonlandırır.
+func (h *CallHandler) supervisePipeline(ctx context.Context, s *state.CallState, actionData map[string]string, stream telephonyv1.TelephonyActionService_RunPipelineClient) {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+	tracker := newPhaseTracker(s.CurrentState)
+
+	budget := h.cfg.PipelineMaxRestarts
+	if budget < 1 {
+		budget = 1
+	}
+	failures := 0
+
+	for {
+		var (
+			failure *pipelineFailure
+			healthy bool
+		)
+
+		if stream == nil {
+			next, err := h.startPipeline(ctx, s, actionData)
+			if err != nil {
+				failure = &pipelineFailure{reason: "TAS_UNREACHABLE", retryable: true, err: err}
+			}
+			stream = next
+		}
+
+		if failure == nil {
+			failure, healthy = h.consumePipeline(ctx, s, tracker, stream)
+			if failure == nil {
+				l.Info().Str("event", "TAS_PIPELINE_EOF").Msg("🏁 SAGA SUCCESS: Pipeline finished naturally.")
+				h.compensatePipeline(s.CallID, "NORMAL_CLEARING")
+				return
+			}
+		}
+		stream = nil
+
+		// Akış devir tamamlandığı veya çağrı bittiği için iptal edildiyse bu bir arıza değildir; çağrı kapatılmaz.
+		if errors.Is(ctx.Err(), context.Canceled) {
+			l.Info().Str("event", "TAS_PIPELINE_RELEASED").Msg("Pipeline bırakıldı, çağrı artık AI'da değil.")
+			return
+		}
+
+		// Pipeline bir kez sağlıklı çalıştıysa sayaç sıfırlanır; bütçe yalnızca ardışık arızaları sınırlar.
+		if healthy {
+			failures = 0
+		}
+		failures++
+
+		if failure.graceful {
+			l.Info().Str("event", "TAS_PIPELINE_CLOSED").Str("reason", failure.reason).Msg("👋 Pipeline akış kararıyla kapatılıyor.")
+			h.compensatePipeline(s.CallID, failure.reason)
+			return
+		}
+
+		// budget yeniden başlatma sayısıdır: ilk çalışmanın arızası bütçeden yemez, budget+1'inci ardışık arıza çağrıyı kapatır.
+		if !failure.retryable || failures > budget {
+			l.Error().Str("event", "TAS_PIPELINE_FAILED").
+				Str("reason", failure.reason).
+				Str("component", failure.component).
+				Bool("retryable", failure.retryable).
+				Int("failures", failures).
+				Err(failure.err).
+				Msg("❌ SAGA FAILURE: Pipeline kurtarılamadı, çağrı sonlandırılıyor.")
+			h.compensatePipeline(s.CallID, failure.reason)
+			return
+		}
+
+		if !h.callStillActive(ctx, s.CallID) {
+			l.Info().Str("event", "TAS_PIPELINE_RETRY_SKIPPED").Msg("Çağrı sonlanmış, pipeline yeniden başlatılmayacak.")
+			return
+		}
+
+		// Ulaşılamayan TAS'ı hemen tekrar denemek yükünü artırır; yeniden başlatma artan bir beklemeyle yapılır.
+		delay := restartBackoff(failures)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		h.failoverModels(ctx, s, failure)
+		metrics.PipelineRestarts.WithLabelValues(failure.component).Inc()
+		l.Warn().Str("event", "TAS_PIPELINE_FAILOVER").
+			Str("reason", failure.reason).
+			Str("component", failure.component).
+			Int("failures", failures).
+			Int("budget", budget).
+			Dur("backoff", delay).
+			Str("stt_model_id", s.VoiceModels.SttModelID).
+			Str("tts_model_id", s.VoiceModels.TtsModelID).
+			Err(failure.err).
+			Msg("🔁 Pipeline yedek modellerle yeniden başlatılıyor.")
+
+		if err := h.playAnnouncementAndWait(ctx, s, constants.AnnounceSystemError); err != nil {
+			l.Warn().Str("event", "ANNOUNCEMENT_PLAY_FAILED").Str("announcement_id", string(constants.AnnounceSystemError)).Err(err).Msg("Hata anonsu çalınamadı.")
+		}
+	}
+}
+
+// consumePipeline, akışı EOF veya arızaya kadar okur. Dönen failure nil ise akış doğal olarak bitmiştir;
+// healthy, akışın en az bir kez RUNNING durumuna ulaşıp ulaşmadığını belirtir.
+func (h *CallHandler) consumePipeline(ctx context.Context, s *state.CallState, tracker *phaseTracker, stream telephonyv1.TelephonyActionService_RunPipelineClient) (*pipelineFailure, bool) {
+	healthy := false
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, healthy
+		}
+		if err != nil {
+			return classifyStreamError(err), healthy
+		}
+
+		sig := parsePipelineSignal(resp.Message)
+		if resp.State == telephonyv1.RunPipelineResponse_STATE_ERROR {
+			return classifyStateError(resp, sig), healthy
+		}
+		if resp.State == telephonyv1.RunPipelineResponse_STATE_RUNNING {
+			healthy = true
+		}
+
+		h.trackPipelinePhase(s, tracker, resp, sig)
+		if sig.Kind == signalKindTranscript {
+			h.captureTranscript(s, sig)
+		}
+		if failure := h.trackCallerInput(ctx, s, sig); failure != nil {
+			return failure, healthy
+		}
+	}
+}
+
+// failoverModels, arızalı bileşenin modelini karantinaya alır ve sıradaki yedeğe geçer.
+// Bileşen bilinmiyorsa modeller değiştirilmeden yeniden denenir.
+func (h *CallHandler) failoverModels(ctx context.Context, s *state.CallState, failure *pipelineFailure) {
+	vm := s.VoiceModels
+	var failed string
+
+	switch failure.component {
+	case "tts":
+		if len(vm.TtsFallbacks) > 0 {
+			failed = vm.TtsModelID
+			vm.TtsModelID, vm.TtsFallbacks = vm.TtsFallbacks[0], vm.TtsFallbacks[1:]
+			vm.TtsSource = "failover"
+		}
+	case "stt":
+		if len(vm.SttFallbacks) > 0 {
+			failed = vm.SttModelID
+			vm.SttModelID, vm.SttFallbacks = vm.SttFallbacks[0], vm.SttFallbacks[1:]
+			vm.SttSource = "failover"
+		}
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if failed != "" {
+		_ = h.stateManager.MarkModelUnavailable(opCtx, failed, modelQuarantine)
+	}
+	_, err := h.stateManager.Update(opCtx, s.CallID, func(cs *state.CallState) error {
+		cs.VoiceModels = vm
+		return nil
+	})
+	if err != nil && !errors.Is(err, state.ErrStateNotFound) {
+		h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Yedek model seçimi kaydedilemedi.")
+	}
+}
+
+// callStillActive, çağrının durumunun hâlâ Redis'te olup olmadığını kontrol eder.
+func (h *CallHandler) callStillActive(ctx context.Context, callID string) bool {
+	opCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	cs, err := h.stateManager.Get(opCtx, callID)
+	return err != nil || cs != nil
+}
+
+// compensatePipeline, pipeline'ın bitişiyle çağrıyı sonlandırır. Devir başka bir replikada tamamlandıysa bu replikadaki
+// akış iptal edilemez; çağrı artık hedefte olduğundan sonlandırılmaz.
+func (h *CallHandler) compensatePipeline(callID, reason string) {
+	opCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if cs, err := h.stateManager.Get(opCtx, callID); err == nil && cs != nil && cs.Transfer != nil && cs.Transfer.Status == transferStatusCompleted {
+		h.log.Info().Str("event", "TAS_PIPELINE_RELEASED").Str("call_id", callID).Str("reason", reason).Msg("Çağrı devredilmiş, pipeline bitişi çağrıyı kapatmayacak.")
+		return
+	}
+	h.compensate(context.Background(), callID, reason)
+}
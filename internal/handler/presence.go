@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/presence"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+)
+
+// presenceCommand, agent.presence.changed GenericEvent'inin payload_json yapısıdır.
+// Ajan arayüzü bu olayı durum değiştikçe ve kalp atışı olarak periyodik gönderir.
+type presenceCommand struct {
+	AgentID string `json:"agentId"`
+	Status  string `json:"status"`
+}
+
+// HandlePresenceChanged, ajan durumunu günceller. ONLINE olan ajan boşta sırasına girer ve kuyruk dağıtıcısı
+// tarafından seçilebilir; BUSY ve WRAP_UP yalnızca agent tarafından verilir, dışarıdan gelirse yenileme sayılır.
+func (h *CallHandler) HandlePresenceChanged(ctx context.Context, event *eventv1.GenericEvent) {
+	var cmd presenceCommand
+	if err := json.Unmarshal([]byte(event.PayloadJson), &cmd); err != nil || cmd.AgentID == "" || event.TenantId == "" {
+		h.log.Warn().Str("event", "PRESENCE_COMMAND_INVALID").Err(err).Msg("Ajan durum olayı çözümlenemedi.")
+		return
+	}
+	l := h.log.With().Str("agent_id", cmd.AgentID).Str("tenant_id", event.TenantId).Logger()
+
+	opCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	status := strings.ToUpper(cmd.Status)
+	var err error
+	switch status {
+	case presence.StatusOnline:
+		// Görüşmedeki/wrap-up'taki ajanın ONLINE kalp atışı durumu ezmemeli; yalnızca süre uzatılır. MISSED ajan
+		// ONLINE'a yalnızca SetPresence ile döner, kalp atışı cevapsız teklif durumunu sıfırlamaz.
+		if cur, gerr := h.presence.Get(opCtx, event.TenantId, cmd.AgentID); gerr == nil && (cur.Status == presence.StatusBusy || cur.Status == presence.StatusWrapUp || cur.Status == presence.StatusMissed) {
+			err = h.presence.Touch(opCtx, event.TenantId, cmd.AgentID)
+		} else {
+			err = h.presence.SetStatus(opCtx, event.TenantId, cmd.AgentID, status)
+		}
+	case presence.StatusBreak, presence.StatusOffline:
+		err = h.presence.SetStatus(opCtx, event.TenantId, cmd.AgentID, status)
+	case presence.StatusBusy, presence.StatusWrapUp:
+		err = h.presence.Touch(opCtx, event.TenantId, cmd.AgentID)
+	default:
+		l.Warn().Str("event", "PRESENCE_STATUS_UNKNOWN").Str("status", cmd.Status).Msg("Bilinmeyen ajan durumu yok sayıldı.")
+		return
+	}
+	if err != nil {
+		l.Warn().Str("event", "PRESENCE_UPDATE_FAILED").Err(err).Msg("Ajan durumu güncellenemedi.")
+		return
+	}
+	l.Debug().Str("event", "PRESENCE_UPDATED").Str("status", status).Msg("Ajan durumu güncellendi.")
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/prompt"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	datav1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/data/v1"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+// Pipeline'a CallContext.state_variables ile giden prompt alanları.
+const (
+	ctxVarSystemPromptID  = "system_prompt_id"
+	ctxVarSystemPrompt    = "system_prompt"
+	ctxVarWelcomePromptID = "welcome_prompt_id"
+	ctxVarWelcomePrompt   = "welcome_prompt"
+)
+
+// runPipelineCallContextField, sentiric-contracts'a önerilen `sentiric.data.v1.CallContext call_context = 9;`
+// alanının RunPipelineRequest'teki numarasıdır. Alan sözleşmenin kullanılan sürümünde henüz yok; bu yüzden mesaja
+// bilinmeyen alan olarak eklenir. Alanı tanıyan TAS onu doğrudan okur, tanımayan eski sürüm yok sayar.
+const runPipelineCallContextField protowire.Number = 9
+
+// systemPromptID, dialplan'da system_prompt_id verilmemişse varsayılan sistem şablonunu döner.
+func systemPromptID(actionData map[string]string) constants.TemplateID {
+	if id := actionData["system_prompt_id"]; id != "" {
+		return constants.TemplateID(id)
+	}
+	return constants.PromptSystemDefault
+}
+
+// pipelineCallContext, sistem ve karşılama şablonlarını çağrı bağlamıyla işleyip sözleşmedeki CallContext
+// mesajına yazar. İşlenemeyen şablon için yalnızca kimlik gider; TAS kendi varsayılanını kullanır.
+func (h *CallHandler) pipelineCallContext(ctx context.Context, s *state.CallState, actionData map[string]string) *datav1.CallContext {
+	cc := &datav1.CallContext{
+		CallId:   s.CallID,
+		TenantId: s.TenantID,
+		StateVariables: map[string]string{
+			ctxVarSystemPromptID:  string(systemPromptID(actionData)),
+			ctxVarWelcomePromptID: string(s.WelcomeTemplateID()),
+		},
+	}
+
+	prompts := []struct {
+		key string
+		id  constants.TemplateID
+	}{
+		{ctxVarSystemPrompt, systemPromptID(actionData)},
+		{ctxVarWelcomePrompt, s.WelcomeTemplateID()},
+	}
+	for _, p := range prompts {
+		text, err := h.prompts.Render(ctx, p.id, s)
+		if err != nil {
+			event := "PROMPT_RENDER_FAILED"
+			if errors.Is(err, prompt.ErrMissingVariable) {
+				event = "PROMPT_VARIABLE_MISSING"
+			}
+			h.log.Warn().Str("event", event).Str("call_id", s.CallID).Str("template_id", string(p.id)).Err(err).Msg("Prompt şablonu işlenemedi, yalnızca şablon kimliği gönderilecek.")
+			continue
+		}
+		cc.StateVariables[p.key] = text
+	}
+	return cc
+}
+
+// attachCallContext, çağrı bağlamını RunPipelineRequest'in call_context alanı olarak istek gövdesine yazar.
+func attachCallContext(req *telephonyv1.RunPipelineRequest, cc *datav1.CallContext) error {
+	body, err := proto.Marshal(cc)
+	if err != nil {
+		return err
+	}
+	raw := protowire.AppendTag(nil, runPipelineCallContextField, protowire.BytesType)
+	raw = protowire.AppendBytes(raw, body)
+	m := req.ProtoReflect()
+	m.SetUnknown(append(m.GetUnknown(), raw...))
+	return nil
+}
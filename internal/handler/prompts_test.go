@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	datav1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/data/v1"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+func TestAttachCallContext(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   *datav1.CallContext
+	}{
+		{
+			name: "empty state variables",
+			cc:   &datav1.CallContext{CallId: "c1", TenantId: "t1"},
+		},
+		{
+			name: "rendered prompts",
+			cc: &datav1.CallContext{CallId: "c1", TenantId: "t1", StateVariables: map[string]string{
+				ctxVarSystemPromptID: "PROMPT_SYSTEM_DEFAULT",
+				ctxVarSystemPrompt:   "Sen yardımcı bir asistansın.",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &telephonyv1.RunPipelineRequest{CallId: "c1", SystemPromptId: "PROMPT_SYSTEM_DEFAULT"}
+			if err := attachCallContext(req, tt.cc); err != nil {
+				t.Fatalf("attachCallContext: %v", err)
+			}
+			wire, err := proto.Marshal(req)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+
+			// Alanı tanıyan bir alıcı gibi call_context'i tel formatından oku.
+			var got *datav1.CallContext
+			for b := wire; len(b) > 0; {
+				num, typ, n := protowire.ConsumeTag(b)
+				if n < 0 {
+					t.Fatalf("bad tag: %v", protowire.ParseError(n))
+				}
+				b = b[n:]
+				if num == runPipelineCallContextField && typ == protowire.BytesType {
+					v, m := protowire.ConsumeBytes(b)
+					got = &datav1.CallContext{}
+					if err := proto.Unmarshal(v, got); err != nil {
+						t.Fatalf("unmarshal call_context: %v", err)
+					}
+					b = b[m:]
+					continue
+				}
+				b = b[protowire.ConsumeFieldValue(num, typ, b):]
+			}
+			if got == nil {
+				t.Fatal("call_context field not found on the wire")
+			}
+			if !proto.Equal(got, tt.cc) {
+				t.Errorf("call_context = %v, want %v", got, tt.cc)
+			}
+
+			// Alanı tanımayan alıcı isteğin geri kalanını bozulmadan okumalı.
+			var legacy telephonyv1.RunPipelineRequest
+			if err := proto.Unmarshal(wire, &legacy); err != nil {
+				t.Fatalf("unmarshal request: %v", err)
+			}
+			if legacy.GetCallId() != "c1" || legacy.GetSystemPromptId() != "PROMPT_SYSTEM_DEFAULT" {
+				t.Errorf("known fields changed: %v", &legacy)
+			}
+		})
+	}
+}
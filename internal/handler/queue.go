@@ -0,0 +1,560 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	workspacev1 "github.com/sentiric/sentiric-agent-service/gen/go/sentiric/agent/v1"
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/queuestats"
+	"github.com/sentiric/sentiric-agent-service/internal/skills"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// maxAssignmentsPerTick, bir kuyrukta tek turda yapılacak en fazla atamadır; diğer kuyrukların beklememesi içindir.
+const maxAssignmentsPerTick = 20
+
+// maxIdleCandidates, bir turda eşleştirmede değerlendirilen en fazla boşta ajandır.
+const maxIdleCandidates = 50
+
+// Dağıtıcı süreleri: tarama/kilit işlemleri ve tek bir atama (sahiplenme, kuyruktan alma, teklif veya devir)
+// ayrı sürelerle yapılır; bir kuyruğun yavaşlığı diğer atamaları süre aşımına düşürmez. Kuyruk kilidi her atamadan
+// önce atamanın süresini kapsayacak şekilde uzatılır; böylece uzun bir tur sürerken kilit başka replikaya geçmez.
+const (
+	dispatchScanTimeout = 5 * time.Second
+	dispatchLockTTL     = 5 * time.Second
+	assignmentTimeout   = 10 * time.Second
+)
+
+// enqueueTimeout, kuyruğa yazma süresidir. Yazma çağıranın context'inden bağımsız yapılır; çağıranın süresinin
+// dolması (ör. dağıtıcı turu) arayanın kapatılmasına yol açmamalıdır.
+const enqueueTimeout = 5 * time.Second
+
+// enqueueCall, çağrıyı tenant kuyruğuna ekler. Daha önce kuyruğa girmiş (ör. reddedilen devirden dönen)
+// çağrı ilk giriş zamanını korur, böylece sırasını kaybetmez.
+func (h *CallHandler) enqueueCall(ctx context.Context, s *state.CallState, actionData map[string]string) {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+
+	queueID := actionData["queue_id"]
+	if queueID == "" {
+		queueID = callqueue.DefaultQueueID
+	}
+	enqueuedAt := time.Now()
+	rejoin := s.Queue != nil && s.Queue.QueueID == queueID
+	if rejoin {
+		enqueuedAt = s.Queue.EnqueuedAt
+	}
+
+	if maxLen, err := strconv.ParseInt(actionData["queue_max_length"], 10, 64); err == nil && maxLen > 0 && !rejoin {
+		if n, err := h.queue.Length(ctx, s.TenantID, queueID); err == nil && n >= maxLen && queueOverflowAction(actionData, queueOverflowFull) != "" {
+			l.Info().Str("event", "QUEUE_FULL").Str("queue_id", queueID).Int64("length", n).Msg("Kuyruk dolu, taşma aksiyonu uygulanıyor.")
+			h.runFallback(ctx, s, actionData, queueOverflowFull)
+			return
+		}
+	}
+
+	var deadline time.Time
+	if maxWait, err := strconv.Atoi(actionData["queue_max_wait_seconds"]); err == nil && maxWait > 0 && queueOverflowAction(actionData, queueOverflowTimeout) != "" {
+		deadline = enqueuedAt.Add(time.Duration(maxWait) * time.Second)
+	}
+
+	priority := h.callPriority(s, actionData)
+	enqCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), enqueueTimeout)
+	defer cancel()
+	pos, err := h.queue.Enqueue(enqCtx, callqueue.Entry{
+		ID:         s.CallID,
+		Kind:       callqueue.KindCall,
+		TenantID:   s.TenantID,
+		QueueID:    queueID,
+		CallID:     s.CallID,
+		FromURI:    s.FromURI,
+		EnqueuedAt: enqueuedAt,
+		Priority:   priority,
+		Language:   s.LanguageCode,
+		ActionData: actionData,
+		Deadline:   deadline,
+	})
+	if err != nil {
+		l.Error().Str("event", "QUEUE_ENQUEUE_FAILED").Str("queue_id", queueID).Err(err).Msg("❌ Çağrı kuyruğa alınamadı.")
+		h.compensate(context.Background(), s.CallID, "QUEUE_UNAVAILABLE")
+		return
+	}
+
+	// Üyelik çağrı durumuna atomik olarak yazılır; arayan bu arada kapattıysa durum silinmiştir ve kuyruğa eklenen
+	// üye geri alınır, aksi halde kapanmış bir çağrı ajana atanabilirdi.
+	updated, err := h.stateManager.Update(enqCtx, s.CallID, func(cs *state.CallState) error {
+		cs.Queue = &state.QueueMembership{QueueID: queueID, EnqueuedAt: enqueuedAt}
+		return nil
+	})
+	if errors.Is(err, state.ErrStateNotFound) {
+		_, _, _ = h.queue.Remove(enqCtx, s.CallID)
+		l.Info().Str("event", "QUEUE_ENQUEUE_ROLLED_BACK").Str("queue_id", queueID).Msg("Arayan kuyruğa alınırken kapattı, kuyruk kaydı geri alındı.")
+		return
+	}
+	if err != nil {
+		l.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Kuyruk üyeliği çağrı durumuna yazılamadı.")
+		s.Queue = &state.QueueMembership{QueueID: queueID, EnqueuedAt: enqueuedAt}
+	} else {
+		s.Queue = updated.Queue
+	}
+
+	if !rejoin {
+		h.stats.RecordOffered(enqCtx, s.TenantID, queueID)
+	}
+	l.Info().Str("event", "QUEUE_ENQUEUED").Str("queue_id", queueID).Int64("position", pos).Int("priority", priority).Msg("👥 Çağrı kuyruğa alındı.")
+	h.publishGenericEvent(enqCtx, constants.EventTypeQueueEntered, s, map[string]interface{}{
+		"callId":   s.CallID,
+		"tenantId": s.TenantID,
+		"queueId":  queueID,
+		"position": pos,
+		"priority": priority,
+	})
+}
+
+// callPriority, çağrının kuyruk önceliğini belirler: dialplan'ın action_data.priority değeri ile tanınan
+// VIP kullanıcının önceliğinden (action_data.vip_priority, yoksa AGENT_QUEUE_VIP_PRIORITY) yüksek olanı.
+func (h *CallHandler) callPriority(s *state.CallState, actionData map[string]string) int {
+	priority, _ := strconv.Atoi(actionData["priority"])
+	if s.User != nil && s.User.VIPTier != "" {
+		vip := h.cfg.QueueVIPPriority
+		if v, err := strconv.Atoi(actionData["vip_priority"]); err == nil {
+			vip = v
+		}
+		priority = max(priority, vip)
+	}
+	return callqueue.ClampPriority(priority)
+}
+
+// Kuyruk taşma sonuçları; aksiyon <sonuç>_fallback veya genel fallback ile seçilir.
+const (
+	queueOverflowFull    = "queue_full"
+	queueOverflowTimeout = "queue_timeout"
+)
+
+// queueOverflowAction, taşma durumunda uygulanacak aksiyonu döner. Aksiyon yine kuyruk ise taşma
+// uygulanmaz (boş string) ve arayan beklemeye devam eder.
+func queueOverflowAction(actionData map[string]string, outcome string) string {
+	if a := fallbackAction(actionData, outcome); a != fallbackQueue {
+		return a
+	}
+	return ""
+}
+
+// expireQueueDeadlines, azami bekleme süresini aşan ve hâlâ kuyrukta bekleyen çağrılara taşma aksiyonunu uygular.
+// Süre, dağıtıcı her turda üyenin giriş zamanından hesaplanan bekleme sınırıyla kontrol edilir; geri arama aksiyonu
+// arayanın yerini korur, diğer aksiyonlarda arayan kuyruktan çıkarılır.
+func (h *CallHandler) expireQueueDeadlines(ctx context.Context) {
+	scanCtx, cancel := context.WithTimeout(ctx, dispatchScanTimeout)
+	defer cancel()
+	due, err := h.queue.DueDeadlines(scanCtx, time.Now())
+	if err != nil {
+		return
+	}
+	for _, id := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		h.expireQueueDeadline(ctx, id)
+	}
+}
+
+func (h *CallHandler) expireQueueDeadline(parent context.Context, entryID string) {
+	ctx, cancel := context.WithTimeout(parent, assignmentTimeout)
+	defer cancel()
+
+	if taken, err := h.queue.TakeDeadline(ctx, entryID); err != nil || !taken {
+		return
+	}
+	e, err := h.queue.Get(ctx, entryID)
+	if err != nil || e == nil || e.Kind != callqueue.KindCall {
+		return
+	}
+	action := queueOverflowAction(e.ActionData, queueOverflowTimeout)
+	if action == "" {
+		return
+	}
+	s, err := h.stateManager.Get(ctx, e.CallID)
+	if err != nil || s == nil || s.Queue == nil {
+		return
+	}
+	l := h.log.With().Str("call_id", e.CallID).Str("queue_id", e.QueueID).Logger()
+
+	if action == fallbackCallback {
+		if err := h.scheduleCallback(ctx, s, "", e.ActionData, queuestats.ExitTimedOut); err == nil {
+			l.Info().Str("event", "QUEUE_TIMEOUT").Dur("waited", time.Since(e.EnqueuedAt)).Msg("⏰ Azami bekleme aşıldı, geri aramaya çevrildi.")
+		}
+		return
+	}
+
+	if _, removed, err := h.queue.Remove(ctx, entryID); err != nil || !removed {
+		return
+	}
+	h.recordQueueExit(ctx, s, queuestats.ExitTimedOut)
+	l.Info().Str("event", "QUEUE_TIMEOUT").Str("fallback", action).Dur("waited", time.Since(e.EnqueuedAt)).Msg("⏰ Azami bekleme aşıldı, taşma aksiyonu uygulanıyor.")
+	h.runFallback(ctx, s, e.ActionData, queueOverflowTimeout)
+}
+
+// leaveQueue, çağrıyı kuyruktan çıkarır ve çıkarılan üyeyi döner; çağrı kuyrukta değilse (ör. geri aramaya
+// dönüşmüşse) nil döner.
+func (h *CallHandler) leaveQueue(ctx context.Context, callID string) *callqueue.Entry {
+	e, removed, err := h.queue.Remove(ctx, callID)
+	if err != nil || !removed {
+		return nil
+	}
+	h.log.Info().Str("event", "QUEUE_LEFT").Str("call_id", callID).Msg("Çağrı kuyruktan çıktı.")
+	return e
+}
+
+// recordQueueAnswered, kuyruktan gelen çağrı ajana bağlandığında cevaplanma süresini istatistiğe yazar. Bekleme,
+// reddedilen devirlerden sonra kuyruğa dönülse de ilk giriş zamanından ölçülür.
+func (h *CallHandler) recordQueueAnswered(ctx context.Context, s *state.CallState) {
+	if s.Transfer == nil || !s.Transfer.FromQueue || s.Queue == nil {
+		return
+	}
+	threshold := h.cfg.ServiceLevelSeconds
+	if v, err := strconv.Atoi(s.Transfer.ActionData["service_level_seconds"]); err == nil && v > 0 {
+		threshold = v
+	}
+	h.stats.RecordAnswered(ctx, s.TenantID, s.Queue.QueueID, time.Since(s.Queue.EnqueuedAt), time.Duration(threshold)*time.Second)
+	s.Queue.Exit = queueExitAnswered
+	_, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		if cs.Queue == nil {
+			return errNotQueued
+		}
+		cs.Queue.Exit = queueExitAnswered
+		return nil
+	})
+	if err != nil && !errors.Is(err, state.ErrStateNotFound) && !errors.Is(err, errNotQueued) {
+		h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Kuyruk çıkışı çağrı durumuna yazılamadı.")
+	}
+}
+
+// queueExitAnswered, ajana bağlanan çağrının kuyruk çıkışıdır; istatistiği RecordAnswered ile yazılır.
+const queueExitAnswered = "answered"
+
+// recordQueueExit, kuyruktan cevaplanmadan ve terk edilmeden çıkan çağrının çıkışını bir kez sayar ve çağrı durumuna
+// işler; böylece çağrı sonradan kapandığında terk sayılmaz. Çıkış, çağrı durumunda atomik olarak işaretlenebildiyse
+// sayılır: çağrı bu arada kapandıysa kapanış zaten terk olarak sayılmıştır.
+func (h *CallHandler) recordQueueExit(ctx context.Context, s *state.CallState, exit string) {
+	if s.Queue == nil || s.Queue.Exit != "" {
+		return
+	}
+	updated, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		if cs.Queue == nil || cs.Queue.Exit != "" {
+			return errQueueExitRecorded
+		}
+		cs.Queue.Exit = exit
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, state.ErrStateNotFound) && !errors.Is(err, errQueueExitRecorded) {
+			h.log.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Kuyruk çıkışı çağrı durumuna yazılamadı.")
+		}
+		return
+	}
+	s.Queue = updated.Queue
+	h.stats.RecordExit(ctx, s.TenantID, s.Queue.QueueID, exit)
+}
+
+// errQueueExitRecorded, çağrının kuyruk çıkışının daha önce işlendiğini bildirir.
+var errQueueExitRecorded = errors.New("queue exit already recorded")
+
+// recordQueueHangup, kapanan çağrı kuyruktan çıkmadan ayrıldıysa terk olarak sayar: kuyrukta beklerken, ajana
+// teklif edilmişken veya ajanında çalarken. Bekleme ilk girişten ölçülür.
+func (h *CallHandler) recordQueueHangup(ctx context.Context, s *state.CallState) {
+	if e := h.leaveQueue(ctx, s.CallID); e != nil {
+		h.stats.RecordAbandoned(ctx, e.TenantID, e.QueueID, time.Since(e.EnqueuedAt))
+		return
+	}
+	if s.Queue == nil || s.Queue.Exit != "" || s.Queue.CallbackID != "" {
+		return
+	}
+	h.stats.RecordAbandoned(ctx, s.TenantID, s.Queue.QueueID, time.Since(s.Queue.EnqueuedAt))
+}
+
+// StartQueueDispatcher, aktif kuyrukları periyodik olarak tarayıp baştaki üyeleri boşta bekleyen ajanlara atar.
+// Her kuyruk kısa süreli bir Redis kilidiyle tek replikada işlenir.
+func (h *CallHandler) StartQueueDispatcher(ctx context.Context, wg *sync.WaitGroup) {
+	interval := time.Duration(h.cfg.QueueDispatchIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.dispatchQueues(ctx)
+			}
+		}
+	}()
+}
+
+func (h *CallHandler) dispatchQueues(ctx context.Context) {
+	scanCtx, cancel := context.WithTimeout(ctx, dispatchScanTimeout)
+	defer cancel()
+
+	h.expireWrapUps(scanCtx)
+	h.expireOffers(scanCtx)
+
+	h.expireQueueDeadlines(ctx)
+
+	refs, err := h.queue.ActiveQueues(scanCtx)
+	if err != nil {
+		h.log.Warn().Str("event", "QUEUE_SCAN_FAILED").Err(err).Msg("Aktif kuyruklar okunamadı.")
+		return
+	}
+	for _, ref := range refs {
+		if ctx.Err() != nil {
+			return
+		}
+		h.dispatchLocked(ctx, ref)
+	}
+}
+
+// dispatchLocked, kuyruğu kilitleyip dağıtır. Kilit ve tarama kendi süresiyle yapılır; önceki kuyruklarda
+// harcanan süre bu kuyruğun işlemlerini kısaltmaz.
+func (h *CallHandler) dispatchLocked(ctx context.Context, ref callqueue.Ref) {
+	lockCtx, cancel := context.WithTimeout(ctx, dispatchScanTimeout)
+	defer cancel()
+
+	token, locked, err := h.queue.TryLock(lockCtx, ref, dispatchLockTTL)
+	if err != nil || !locked {
+		return
+	}
+	h.dispatchQueue(ctx, ref, token)
+
+	unlockCtx, unlockCancel := context.WithTimeout(context.WithoutCancel(ctx), dispatchScanTimeout)
+	defer unlockCancel()
+	_ = h.queue.Unlock(unlockCtx, ref, token)
+}
+
+// dispatchQueue, kuyruğun başındaki üyeleri sırayla boştaki ajanlarla eşleştirir. Şartlarını karşılayan ajan
+// bulunamayan üye atlanır ve arkasındakiler beklemez (head-of-line blocking); üye bir sonraki turda, gerekirse
+// gevşetilmiş şartlarla yeniden denenir. Her atama kendi süresiyle yapılır; kilit uzatılamazsa (süresi dolup
+// başka replikaya geçtiyse) tur bırakılır.
+func (h *CallHandler) dispatchQueue(ctx context.Context, ref callqueue.Ref, lockToken string) {
+	scanCtx, cancel := context.WithTimeout(ctx, dispatchScanTimeout)
+	defer cancel()
+
+	waiting, err := h.queue.Peek(scanCtx, ref.TenantID, ref.QueueID, maxAssignmentsPerTick)
+	if err != nil {
+		return
+	}
+	if len(waiting) == 0 {
+		_ = h.queue.Deactivate(scanCtx, ref)
+		return
+	}
+
+	idle, err := h.presence.IdleAgents(scanCtx, ref.TenantID, maxIdleCandidates)
+	if err != nil || len(idle) == 0 {
+		return
+	}
+
+	var agentSkills map[string]map[string]int
+	now := time.Now()
+	for _, w := range waiting {
+		if len(idle) == 0 || ctx.Err() != nil {
+			return
+		}
+		reqs := skills.WithLanguage(skills.ParseRequirements(w.Entry.ActionData["required_skills"]), w.Entry.Language)
+		if len(reqs) > 0 && agentSkills == nil {
+			if agentSkills, err = database.GetAgentSkills(scanCtx, h.db, ref.TenantID, idle); err != nil {
+				h.log.Warn().Str("event", "AGENT_SKILLS_READ_FAILED").Str("tenant_id", ref.TenantID).Err(err).Msg("Ajan yetenekleri okunamadı, dağıtım erteleniyor.")
+				return
+			}
+		}
+		reqs = skills.Relax(reqs, skills.RelaxSteps(now.Sub(w.Entry.EnqueuedAt), h.skillRelaxAfter(w.Entry.ActionData)))
+
+		if held, err := h.extendDispatchLock(ctx, ref, lockToken); err != nil || !held {
+			h.log.Warn().Str("event", "QUEUE_LOCK_LOST").Str("tenant_id", ref.TenantID).Str("queue_id", ref.QueueID).Err(err).Msg("Kuyruk kilidi kaybedildi, dağıtım turu bırakılıyor.")
+			return
+		}
+		idle = h.assignWaiting(ctx, ref, w, idle, agentSkills, reqs)
+	}
+}
+
+// extendDispatchLock, kuyruk kilidini bir atamanın süresini kapsayacak kadar uzatır. Tarama süresi önceki atamalarda
+// tükenmiş olabileceğinden kendi süresiyle yapılır.
+func (h *CallHandler) extendDispatchLock(ctx context.Context, ref callqueue.Ref, token string) (bool, error) {
+	lockCtx, cancel := context.WithTimeout(ctx, dispatchScanTimeout)
+	defer cancel()
+	return h.queue.ExtendLock(lockCtx, ref, token, dispatchLockTTL+assignmentTimeout)
+}
+
+// assignWaiting, üyeye uygun ajanı sahiplenip üyeyi kuyruktan alır ve teklif eder; kalan adayları döner.
+func (h *CallHandler) assignWaiting(ctx context.Context, ref callqueue.Ref, w callqueue.Waiting, idle []string, agentSkills map[string]map[string]int, reqs []skills.Requirement) []string {
+	assignCtx, cancel := context.WithTimeout(ctx, assignmentTimeout)
+	defer cancel()
+
+	idle, agentID := h.claimMatchingAgent(assignCtx, ref.TenantID, idle, agentSkills, reqs)
+	if agentID == "" {
+		return idle
+	}
+	e, removed, err := h.queue.Remove(assignCtx, w.Entry.ID)
+	if err != nil || !removed {
+		_ = h.presence.Release(assignCtx, ref.TenantID, agentID)
+		return idle
+	}
+	h.offerEntry(assignCtx, *e, w.Score, agentID)
+	return idle
+}
+
+// claimMatchingAgent, şartları karşılayan en iyi ajanı sahiplenir ve adaylardan çıkarır. Bu arada boşta olmaktan
+// çıkmış ajanlar da adaylardan düşülür. Uygun ajan yoksa boş string döner.
+func (h *CallHandler) claimMatchingAgent(ctx context.Context, tenantID string, idle []string, agentSkills map[string]map[string]int, reqs []skills.Requirement) ([]string, string) {
+	for {
+		agentID, ok := skills.Best(idle, agentSkills, reqs)
+		if !ok {
+			return idle, ""
+		}
+		idle = slices.DeleteFunc(idle, func(id string) bool { return id == agentID })
+		if claimed, err := h.presence.Claim(ctx, tenantID, agentID); err == nil && claimed {
+			return idle, agentID
+		}
+	}
+}
+
+// skillRelaxAfter, yetenek şartlarının kaç saniyede bir seviye gevşetileceğini döner: önce kuyruğa özel
+// skill_relax_after_seconds, yoksa AGENT_SKILL_RELAX_AFTER_SECONDS. Sıfır gevşetmeyi kapatır.
+func (h *CallHandler) skillRelaxAfter(actionData map[string]string) time.Duration {
+	seconds := h.cfg.SkillRelaxAfterSeconds
+	if v, err := strconv.Atoi(actionData["skill_relax_after_seconds"]); err == nil && v >= 0 {
+		seconds = v
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// assignEntry, kuyruktan alınan üyeyi ajana bağlar: canlı çağrı ajana devredilir, geri arama için giden çağrı başlatılır.
+func (h *CallHandler) assignEntry(ctx context.Context, e callqueue.Entry, score float64, agentID string) {
+	l := h.log.With().Str("tenant_id", e.TenantID).Str("queue_id", e.QueueID).Str("agent_id", agentID).Logger()
+
+	if e.Kind == callqueue.KindCallback {
+		l.Info().Str("event", "QUEUE_CALLBACK_ASSIGNED").Str("entry_id", e.ID).Msg("📲 Geri arama sırası geldi, ajan atandı.")
+		h.dialCallback(ctx, e, score, agentID)
+		return
+	}
+
+	s, err := h.stateManager.Get(ctx, e.CallID)
+	if err != nil || s == nil {
+		_ = h.presence.Release(ctx, e.TenantID, agentID)
+		return
+	}
+	l.Info().Str("event", "QUEUE_CALL_ASSIGNED").Str("call_id", e.CallID).Msg("✅ Kuyruktaki çağrı ajana atandı.")
+
+	mode := transferModeBlind
+	if strings.EqualFold(e.ActionData["transfer_mode"], transferModeAttended) {
+		mode = transferModeAttended
+	}
+	h.startTransfer(ctx, s, transferRequest{
+		Mode:       mode,
+		TargetURI:  h.agentTargetURI(agentID),
+		AgentID:    agentID,
+		FromQueue:  true,
+		ActionData: e.ActionData,
+	})
+}
+
+// GetQueuePosition, üyenin kuyruktaki sırasını, önceliğini ve beklemeyle kazandığı öncelik seviyesini döner.
+func (h *CallHandler) GetQueuePosition(ctx context.Context, req *workspacev1.GetQueuePositionRequest) (*workspacev1.GetQueuePositionResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.EntryId == "" {
+		return nil, status.Error(codes.InvalidArgument, "entry_id zorunludur")
+	}
+	p, err := h.queue.Locate(ctx, req.EntryId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "kuyruk okunamadı")
+	}
+	// [ARCH-COMPLIANCE] Tenant Isolation: başka tenant'ın üyesi kuyrukta yokmuş gibi görünür.
+	if p == nil || p.Entry.TenantID != agent.TenantID {
+		return &workspacev1.GetQueuePositionResponse{Queued: false}, nil
+	}
+	return &workspacev1.GetQueuePositionResponse{
+		Queued:      true,
+		QueueId:     p.Entry.QueueID,
+		Position:    p.Position,
+		QueueLength: p.Length,
+		Priority:    int32(p.Entry.Priority),
+		AgedLevels:  int32(p.AgedLevels),
+		WaitSeconds: int64(p.Waited.Seconds()),
+		EnqueuedAt:  timestamppb.New(p.Entry.EnqueuedAt),
+	}, nil
+}
+
+// GetQueueStats, tenant kuyruklarının anlık göstergelerini ve ajanların duruma göre dağılımını döner.
+func (h *CallHandler) GetQueueStats(ctx context.Context, req *workspacev1.GetQueueStatsRequest) (*workspacev1.GetQueueStatsResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// [ARCH-COMPLIANCE] Tenant Isolation: yalnızca isteği yapan tenant'ın kuyrukları okunur.
+	refs := []callqueue.Ref{{TenantID: agent.TenantID, QueueID: req.QueueId}}
+	if req.QueueId == "" {
+		if refs, err = h.stats.Queues(ctx, agent.TenantID); err != nil {
+			return nil, status.Error(codes.Internal, "istatistikler okunamadı")
+		}
+	}
+
+	resp := &workspacev1.GetQueueStatsResponse{Queues: make([]*workspacev1.QueueStats, 0, len(refs))}
+	for _, ref := range refs {
+		snap, err := h.stats.Snapshot(ctx, ref.TenantID, ref.QueueID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "istatistikler okunamadı")
+		}
+		resp.Queues = append(resp.Queues, &workspacev1.QueueStats{
+			QueueId:            snap.QueueID,
+			Waiting:            snap.Waiting,
+			LongestWaitSeconds: snap.LongestWait.Seconds(),
+			IntervalStart:      timestamppb.New(snap.IntervalStart),
+			Offered:            snap.Offered,
+			Answered:           snap.Answered,
+			Abandoned:          snap.Abandoned,
+			AsaSeconds:         snap.ASA().Seconds(),
+			AbandonRate:        snap.AbandonRate(),
+			ServiceLevel:       snap.ServiceLevel(),
+			TimedOut:           snap.TimedOut,
+			Callbacks:          snap.Callbacks,
+			Voicemails:         snap.Voicemails,
+		})
+	}
+
+	agents, err := h.stats.Agents(ctx, agent.TenantID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ajan durumları okunamadı")
+	}
+	resp.AgentsByStatus = make(map[string]int32, len(agents))
+	for st, n := range agents {
+		resp.AgentsByStatus[st] = int32(n)
+	}
+	return resp, nil
+}
+
+// releaseAgent, devir/giden çağrı sona erdiğinde veya başarısız olduğunda ajanı tekrar boşta sırasına alır.
+func (h *CallHandler) releaseAgent(ctx context.Context, tenantID, agentID string) {
+	if agentID == "" {
+		return
+	}
+	if err := h.presence.Release(ctx, tenantID, agentID); err != nil {
+		h.log.Warn().Str("event", "AGENT_RELEASE_FAILED").Str("agent_id", agentID).Err(err).Msg("Ajan tekrar ONLINE yapılamadı.")
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/config"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+func TestCallPriority(t *testing.T) {
+	h := &CallHandler{cfg: &config.Config{QueueVIPPriority: 5}}
+	vip := &state.IdentifiedUser{ID: "u1", VIPTier: "vip"}
+	regular := &state.IdentifiedUser{ID: "u2"}
+
+	tests := []struct {
+		name       string
+		user       *state.IdentifiedUser
+		actionData map[string]string
+		want       int
+	}{
+		{"unknown caller without priority", nil, nil, 0},
+		{"dialplan priority", regular, map[string]string{"priority": "3"}, 3},
+		{"unreadable dialplan priority is normal", nil, map[string]string{"priority": "high"}, 0},
+		{"vip gets the default vip priority", vip, nil, 5},
+		{"vip priority from the dialplan", vip, map[string]string{"vip_priority": "8"}, 8},
+		{"higher dialplan priority wins over vip", vip, map[string]string{"priority": "7"}, 7},
+		{"higher vip priority wins over dialplan", vip, map[string]string{"priority": "2", "vip_priority": "6"}, 6},
+		{"vip priority is ignored for regular users", regular, map[string]string{"vip_priority": "9"}, 0},
+		{"priority is clamped", vip, map[string]string{"vip_priority": "42"}, callqueue.MaxPriority},
+		{"negative priority is clamped", nil, map[string]string{"priority": "-4"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &state.CallState{User: tt.user}
+			if got := h.callPriority(s, tt.actionData); got != tt.want {
+				t.Errorf("callPriority() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// transcriptSenders, pipeline'ın gönderdiği konuşmacı adlarını transcript sender_type değerlerine eşler.
+var transcriptSenders = map[string]string{
+	"USER":      "USER",
+	"CALLER":    "USER",
+	"AI":        "AI",
+	"ASSISTANT": "AI",
+	"AGENT":     "AGENT",
+}
+
+// captureTranscript, pipeline'dan gelen ifadeyi asenkron yazıcıya devreder; akış döngüsünü bloklamaz.
+func (h *CallHandler) captureTranscript(s *state.CallState, sig pipelineSignal) {
+	text := strings.TrimSpace(sig.Text)
+	if text == "" {
+		return
+	}
+
+	sender, ok := transcriptSenders[strings.ToUpper(sig.Sender)]
+	if !ok {
+		h.log.Debug().Str("event", "TRANSCRIPT_UNKNOWN_SENDER").Str("call_id", s.CallID).Str("sender", sig.Sender).Msg("Bilinmeyen konuşmacı, ifade atlanıyor.")
+		return
+	}
+
+	h.transcripts.Enqueue(database.TranscriptEntry{
+		CallID:      s.CallID,
+		SenderType:  sender,
+		MessageText: text,
+		CreatedAt:   time.Now(),
+	})
+}
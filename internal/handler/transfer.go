@@ -0,0 +1,455 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+	sipv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/sip/v1"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+// Devir modları.
+const (
+	transferModeBlind    = "BLIND"
+	transferModeAttended = "ATTENDED"
+)
+
+// Devir durumları. STARTING, B2BUA isteği sürerken yeni bir devrin başlatılmasını engeller.
+// RINGING → CONSULTING → COMPLETED akışı yalnızca attended devirde görülür.
+const (
+	transferStatusStarting   = "STARTING"
+	transferStatusRinging    = "RINGING"
+	transferStatusConsulting = "CONSULTING"
+	transferStatusCompleted  = "COMPLETED"
+	transferStatusCancelled  = "CANCELLED"
+	transferStatusDeclined   = "DECLINED"
+	transferStatusFailed     = "FAILED"
+)
+
+// errTransferNotActive, komutun beklediği devir durumunun (artık) geçerli olmadığını bildirir.
+var errTransferNotActive = errors.New("transfer not in expected state")
+
+// errTransferActive, çağrıda süren bir devir varken yeni devir istendiğini bildirir.
+var errTransferActive = errors.New("transfer already active")
+
+// transferRequest, bir devrin başlatılması için gereken bilgilerdir.
+type transferRequest struct {
+	Mode       string
+	TargetURI  string
+	AgentID    string
+	FromQueue  bool
+	ActionData map[string]string
+}
+
+// transferCommand, agent.transfer.* GenericEvent'lerinin payload_json yapısıdır.
+type transferCommand struct {
+	CallID    string `json:"callId"`
+	Mode      string `json:"mode,omitempty"`
+	TargetURI string `json:"targetUri,omitempty"`
+	AgentID   string `json:"agentId,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// agentTargetURI, ajan kimliğinden B2BUA'nın arayacağı SIP URI'sini üretir.
+func (h *CallHandler) agentTargetURI(agentID string) string {
+	return strings.ReplaceAll(h.cfg.AgentTargetURITemplate, "{agent_id}", agentID)
+}
+
+// startTransfer, devri moduna göre başlatır: blind devirde çağrı doğrudan B2BUA'ya devredilir,
+// attended devirde önce hedefle bir danışma bacağı açılır ve tamamlama/iptal komutu beklenir.
+func (h *CallHandler) startTransfer(ctx context.Context, s *state.CallState, req transferRequest) {
+	l := h.log.With().Str("call_id", s.CallID).Str("mode", req.Mode).Str("target_uri", req.TargetURI).Logger()
+
+	// Devir kaydı, çağrıda süren bir devir yoksa atomik olarak yazılır; eşzamanlı iki istekten yalnızca biri ilerler.
+	transfer := &state.TransferState{
+		Mode:       req.Mode,
+		Status:     transferStatusStarting,
+		TargetURI:  req.TargetURI,
+		AgentID:    req.AgentID,
+		FromQueue:  req.FromQueue,
+		StartedAt:  time.Now(),
+		ActionData: req.ActionData,
+	}
+	updated, err := h.stateManager.Update(ctx, s.CallID, func(cs *state.CallState) error {
+		if transferActive(cs.Transfer) {
+			return errTransferActive
+		}
+		transfer.PriorState = cs.CurrentState
+		cs.Transfer = transfer
+		return nil
+	})
+	if errors.Is(err, errTransferActive) {
+		l.Warn().Str("event", "TRANSFER_ALREADY_ACTIVE").Msg("Çağrıda süren bir devir var, yeni istek yok sayıldı.")
+		return
+	}
+	if err != nil {
+		l.Warn().Str("event", "TRANSFER_STATE_SAVE_FAILED").Err(err).Msg("Devir başlatılamadı, çağrı durumu yazılamadı.")
+		if req.FromQueue {
+			h.releaseAgent(ctx, s.TenantID, req.AgentID)
+		}
+		return
+	}
+	s = updated
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if req.Mode == transferModeBlind {
+		resp, err := h.clients.B2BUA.TransferCall(reqCtx, &sipv1.TransferCallRequest{
+			ExistingCallId:    s.CallID,
+			TransferTargetUri: req.TargetURI,
+		})
+		if err == nil && !resp.Success {
+			err = errors.New("B2BUA devri reddetti")
+		}
+		if err != nil {
+			l.Error().Str("event", "TRANSFER_FAILED").Err(err).Msg("❌ Blind devir başarısız.")
+			h.failTransfer(ctx, s, transferStatusFailed)
+			return
+		}
+		s, err = h.transitionTransfer(ctx, s.CallID, transferStatusCompleted, func(cs *state.CallState) {
+			cs.CurrentState = constants.StateTransferred
+		}, transferStatusStarting)
+		if err != nil {
+			l.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Devir sonrası çağrı durumu kaydedilemedi.")
+			return
+		}
+		if s.User != nil && req.AgentID != "" {
+			_ = h.stateManager.SetAgentAffinity(ctx, s.User.ID, req.AgentID)
+		}
+		h.assignCallAgent(ctx, s, req.AgentID)
+		h.pipelines.end(s.CallID)
+		h.recordQueueAnswered(ctx, s)
+		l.Info().Str("event", "TRANSFER_COMPLETED").Msg("➡️ Çağrı hedefe devredildi (blind).")
+		h.publishTransferState(ctx, s)
+		return
+	}
+
+	resp, err := h.clients.B2BUA.InitiateCall(reqCtx, &sipv1.InitiateCallRequest{
+		CallId:  s.CallID,
+		FromUri: s.FromURI,
+		ToUri:   req.TargetURI,
+	})
+	if err == nil && (!resp.Success || resp.NewCallId == "") {
+		err = errors.New("B2BUA danışma bacağını açamadı")
+	}
+	if err != nil {
+		l.Error().Str("event", "TRANSFER_CONSULT_FAILED").Err(err).Msg("❌ Danışma bacağı açılamadı.")
+		h.failTransfer(ctx, s, transferStatusFailed)
+		return
+	}
+
+	consultCallID := resp.NewCallId
+	if err := h.stateManager.SetConsultLeg(ctx, consultCallID, s.CallID); err != nil {
+		l.Warn().Str("event", "TRANSFER_CONSULT_SAVE_FAILED").Err(err).Msg("Danışma bacağı eşlemesi kaydedilemedi.")
+	}
+	s, err = h.transitionTransfer(ctx, s.CallID, transferStatusRinging, func(cs *state.CallState) {
+		cs.Transfer.ConsultCallID = consultCallID
+		cs.CurrentState = constants.StateTransferring
+	}, transferStatusStarting)
+	if err != nil {
+		// Arayan bu arada kapattı; açılan danışma bacağı açık bırakılmaz.
+		l.Warn().Str("event", "TRANSFER_CONSULT_ORPHANED").Err(err).Msg("Danışma bacağı açıldı ama devir artık geçerli değil, bacak kapatılıyor.")
+		_ = h.stateManager.DeleteConsultLeg(ctx, consultCallID)
+		h.terminateLeg(ctx, consultCallID, "transfer_settled")
+		return
+	}
+	l.Info().Str("event", "TRANSFER_CONSULT_RINGING").Str("consult_call_id", consultCallID).Msg("📞 Danışma bacağı çalıyor.")
+	h.publishTransferState(ctx, s)
+
+	callID := s.CallID
+	h.ringWatches.start(consultCallID, time.Duration(h.cfg.BridgeRingTimeoutSeconds)*time.Second, func() { h.onConsultRingTimeout(callID, consultCallID) })
+}
+
+// onConsultRingTimeout, danışma bacağı zamanında cevaplanmazsa devri reddedilmiş sayar.
+// Bacak cevaplanır, kapanır veya devir sonuçlanırsa izleyici iptal edildiğinden çağrılmaz.
+func (h *CallHandler) onConsultRingTimeout(callID, consultCallID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if h.declineTransfer(ctx, callID, consultCallID, transferStatusRinging, "no_answer") {
+		h.log.Info().Str("event", "TRANSFER_CONSULT_NO_ANSWER").Str("call_id", callID).Str("consult_call_id", consultCallID).Msg("⏰ Danışılan hedef cevap vermedi.")
+	}
+}
+
+// transferActive, devrin hâlâ sürüp sürmediğini döner.
+func transferActive(t *state.TransferState) bool {
+	return t != nil && (t.Status == transferStatusStarting || t.Status == transferStatusRinging || t.Status == transferStatusConsulting)
+}
+
+// transitionTransfer, devri yalnızca beklenen durumlardan birindeyse atomik olarak yeni duruma taşır.
+// apply nil değilse aynı güncellemede çağrı durumuna uygulanır.
+func (h *CallHandler) transitionTransfer(ctx context.Context, callID, next string, apply func(cs *state.CallState), expected ...string) (*state.CallState, error) {
+	return h.stateManager.Update(ctx, callID, func(cs *state.CallState) error {
+		if cs.Transfer == nil {
+			return errTransferNotActive
+		}
+		for _, st := range expected {
+			if cs.Transfer.Status == st {
+				cs.Transfer.Status = next
+				if apply != nil {
+					apply(cs)
+				}
+				return nil
+			}
+		}
+		return errTransferNotActive
+	})
+}
+
+// handleConsultLegStarted, danışma bacağının cevaplanmasını yakalar. Olay bir devre ait değilse false döner.
+func (h *CallHandler) handleConsultLegStarted(ctx context.Context, consultCallID string) bool {
+	callID, err := h.stateManager.GetConsultLeg(ctx, consultCallID)
+	if err != nil || callID == "" {
+		return false
+	}
+
+	h.ringWatches.stop(consultCallID)
+	s, err := h.transitionTransfer(ctx, callID, transferStatusConsulting, nil, transferStatusRinging)
+	if err != nil {
+		h.terminateLeg(ctx, consultCallID, "transfer_settled")
+		return true
+	}
+	h.log.Info().Str("event", "TRANSFER_CONSULTING").Str("call_id", callID).Str("consult_call_id", consultCallID).Msg("🗣️ Danışılan hedef cevapladı, tamamlama bekleniyor.")
+	h.publishTransferState(ctx, s)
+	return true
+}
+
+// handleConsultLegEnded, danışma bacağının kapanmasını işler: tamamlanmadan kapanan bacak devrin reddidir.
+// Olay bir devre ait değilse false döner.
+func (h *CallHandler) handleConsultLegEnded(ctx context.Context, consultCallID, reason string) bool {
+	callID, err := h.stateManager.GetConsultLeg(ctx, consultCallID)
+	if err != nil || callID == "" {
+		return false
+	}
+	h.ringWatches.stop(consultCallID)
+	h.declineTransfer(ctx, callID, consultCallID, "", reason)
+	return true
+}
+
+// completeTransfer, attended devirde arayanı danışma bacağına bağlar. Köprü kurulduktan sonra
+// bacak normal bir köprü olarak izlenir (bkz. bridge.go) ve süre konuşmaya yazılır.
+func (h *CallHandler) completeTransfer(ctx context.Context, callID string) {
+	l := h.log.With().Str("call_id", callID).Logger()
+
+	s, err := h.transitionTransfer(ctx, callID, transferStatusCompleted, nil, transferStatusConsulting)
+	if err != nil {
+		l.Warn().Str("event", "TRANSFER_COMPLETE_REJECTED").Err(err).Msg("Tamamlanacak aktif bir danışma yok.")
+		return
+	}
+	t := s.Transfer
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := h.clients.TelephonyAction.BridgeCall(reqCtx, &telephonyv1.BridgeCallRequest{
+		CallIdA: callID,
+		CallIdB: t.ConsultCallID,
+	})
+	if err == nil && !resp.Success {
+		err = errors.New(resp.Message)
+	}
+	if err != nil {
+		l.Error().Str("event", "TRANSFER_BRIDGE_FAILED").Err(err).Msg("❌ Arayan danışılan hedefe bağlanamadı.")
+		_ = h.stateManager.DeleteConsultLeg(ctx, t.ConsultCallID)
+		h.terminateLeg(ctx, t.ConsultCallID, "transfer_failed")
+		h.failTransfer(ctx, s, transferStatusFailed)
+		return
+	}
+
+	now := time.Now()
+	_ = h.stateManager.DeleteConsultLeg(ctx, t.ConsultCallID)
+	s, err = h.stateManager.Update(ctx, callID, func(cs *state.CallState) error {
+		cs.CurrentState = constants.StateTransferred
+		cs.Bridge = &state.BridgeLeg{
+			PeerCallID: t.ConsultCallID,
+			TargetURI:  t.TargetURI,
+			StartedAt:  t.StartedAt,
+			AnsweredAt: now,
+			Outcome:    bridgeOutcomeAnswered,
+		}
+		return nil
+	})
+	if err != nil {
+		l.Warn().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Devir sonrası çağrı durumu kaydedilemedi.")
+		return
+	}
+	_ = h.stateManager.SetBridgePeer(ctx, t.ConsultCallID, callID)
+	if s.User != nil && t.AgentID != "" {
+		_ = h.stateManager.SetAgentAffinity(ctx, s.User.ID, t.AgentID)
+	}
+	h.assignCallAgent(ctx, s, t.AgentID)
+	h.pipelines.end(callID)
+
+	h.recordQueueAnswered(ctx, s)
+	l.Info().Str("event", "TRANSFER_COMPLETED").Str("consult_call_id", t.ConsultCallID).Msg("➡️ Çağrı danışılan hedefe devredildi (attended).")
+	h.publishTransferState(ctx, s)
+}
+
+// cancelTransfer, danışmayı iptal eder; arayan mevcut görüşmesine döner (kuyruktan geldiyse kuyruğa döner).
+func (h *CallHandler) cancelTransfer(ctx context.Context, callID string) {
+	s, err := h.transitionTransfer(ctx, callID, transferStatusCancelled, restorePriorState, transferStatusRinging, transferStatusConsulting)
+	if err != nil {
+		h.log.Warn().Str("event", "TRANSFER_CANCEL_REJECTED").Str("call_id", callID).Err(err).Msg("İptal edilecek aktif bir danışma yok.")
+		return
+	}
+	h.ringWatches.stop(s.Transfer.ConsultCallID)
+	_ = h.stateManager.DeleteConsultLeg(ctx, s.Transfer.ConsultCallID)
+	h.terminateLeg(ctx, s.Transfer.ConsultCallID, "transfer_cancelled")
+
+	h.log.Info().Str("event", "TRANSFER_CANCELLED").Str("call_id", callID).Msg("↩️ Devir iptal edildi.")
+	h.publishTransferState(ctx, s)
+	h.resumeAfterTransfer(ctx, s)
+}
+
+// declineTransfer, danışılan hedefin reddini işler. expected boş değilse yalnızca o durumdaki devir reddedilir.
+func (h *CallHandler) declineTransfer(ctx context.Context, callID, consultCallID, expected, reason string) bool {
+	statuses := []string{transferStatusRinging, transferStatusConsulting}
+	if expected != "" {
+		statuses = []string{expected}
+	}
+	s, err := h.transitionTransfer(ctx, callID, transferStatusDeclined, restorePriorState, statuses...)
+	if err != nil {
+		return false
+	}
+	h.ringWatches.stop(consultCallID)
+	_ = h.stateManager.DeleteConsultLeg(ctx, consultCallID)
+	h.terminateLeg(ctx, consultCallID, "transfer_declined")
+
+	h.log.Info().Str("event", "TRANSFER_DECLINED").Str("call_id", callID).Str("agent_id", s.Transfer.AgentID).Str("reason", reason).Msg("🙅 Danışılan hedef devri reddetti.")
+	h.publishTransferState(ctx, s)
+	h.resumeAfterTransfer(ctx, s)
+	return true
+}
+
+// failTransfer, başlatılamayan devri kaydeder; arayan mevcut görüşmesine döner (kuyruktan geldiyse kuyruğa döner).
+func (h *CallHandler) failTransfer(ctx context.Context, s *state.CallState, status string) {
+	// Attended devirde köprü kurulamazsa devir COMPLETED'a geçmiş olur; o durumdan da geri alınır.
+	updated, err := h.transitionTransfer(ctx, s.CallID, status, restorePriorState, transferStatusStarting, transferStatusConsulting, transferStatusCompleted)
+	if err != nil {
+		h.log.Warn().Str("event", "TRANSFER_STATE_SAVE_FAILED").Str("call_id", s.CallID).Err(err).Msg("Başarısız devir kaydedilemedi.")
+		if errors.Is(err, state.ErrStateNotFound) {
+			return
+		}
+		s.Transfer.Status = status
+	} else {
+		s = updated
+	}
+	h.publishTransferState(ctx, s)
+	h.resumeAfterTransfer(ctx, s)
+}
+
+// restorePriorState, sonuçlanmayan devirden sonra çağrıyı devirden önceki diyalog durumuna döndürür.
+func restorePriorState(cs *state.CallState) {
+	if cs.Transfer.PriorState != "" {
+		cs.CurrentState = cs.Transfer.PriorState
+	}
+}
+
+// resumeAfterTransfer, sonuçlanmayan devirden sonra arayanı sessizlikte bırakmaz. Kuyruktan gelen çağrı kuyruğa
+// döner; AI ile konuşan arayana devrin yapılamadığı anonsu çalınır (pipeline çalışmıyorsa yeniden başlatılır);
+// bir ajanla köprülü arayan o görüşmede kalır.
+func (h *CallHandler) resumeAfterTransfer(ctx context.Context, s *state.CallState) {
+	if s.Transfer.FromQueue {
+		h.releaseAgent(ctx, s.TenantID, s.Transfer.AgentID)
+		h.revertToQueue(ctx, s)
+		return
+	}
+
+	switch s.CurrentState {
+	case constants.StateBridged, constants.StateTransferred:
+		return
+	}
+	if err := h.playAnnouncement(ctx, s, constants.AnnounceTransferFailed); err != nil {
+		h.log.Warn().Str("event", "ANNOUNCEMENT_PLAY_FAILED").Str("call_id", s.CallID).Str("announcement_id", string(constants.AnnounceTransferFailed)).Err(err).Msg("Anons çalınamadı.")
+	}
+	if !s.PipelineActive {
+		h.log.Info().Str("event", "TRANSFER_RESUME_AI").Str("call_id", s.CallID).Msg("🤖 Devir yapılamadı, AI konuşması başlatılıyor.")
+		h.runTASPipeline(context.Background(), s, s.Transfer.ActionData)
+	}
+}
+
+// revertToQueue, reddedilen/başarısız devirden sonra çağrıyı kuyruğa döndürür. Aynı ajana
+// tekrar yönlenmemesi için hedef ve tercihli ajan eşleşmesi devre dışı bırakılır.
+func (h *CallHandler) revertToQueue(ctx context.Context, s *state.CallState) {
+	data := make(map[string]string, len(s.Transfer.ActionData)+1)
+	for k, v := range s.Transfer.ActionData {
+		data[k] = v
+	}
+	delete(data, "target_agent_id")
+	data["skip_affinity"] = "true"
+
+	h.log.Info().Str("event", "TRANSFER_REVERT_TO_QUEUE").Str("call_id", s.CallID).Msg("🔁 Çağrı kuyruğa geri alınıyor.")
+	h.handleEnqueueCall(ctx, s, data)
+}
+
+func (h *CallHandler) publishTransferState(ctx context.Context, s *state.CallState) {
+	t := s.Transfer
+	h.publishGenericEvent(ctx, constants.EventTypeTransferStateChanged, s, map[string]interface{}{
+		"callId":        s.CallID,
+		"tenantId":      s.TenantID,
+		"mode":          t.Mode,
+		"status":        t.Status,
+		"targetUri":     t.TargetURI,
+		"agentId":       t.AgentID,
+		"consultCallId": t.ConsultCallID,
+	})
+}
+
+// HandleTransferCommand, agent.transfer.* komutlarını (requested/complete/cancel/declined) işler.
+func (h *CallHandler) HandleTransferCommand(ctx context.Context, event *eventv1.GenericEvent) {
+	var cmd transferCommand
+	if err := json.Unmarshal([]byte(event.PayloadJson), &cmd); err != nil || cmd.CallID == "" {
+		h.log.Warn().Str("event", "TRANSFER_COMMAND_INVALID").Str("type", event.EventType).Err(err).Msg("Devir komutu çözümlenemedi.")
+		return
+	}
+	l := h.log.With().Str("call_id", cmd.CallID).Str("type", event.EventType).Logger()
+
+	// [ARCH-COMPLIANCE] Tenant izolasyonu: tenant'sız veya başka tenant adına gelen komut uygulanmaz.
+	if event.TenantId == "" {
+		l.Warn().Str("event", "TRANSFER_TENANT_MISSING").Msg("Devir komutunda tenant yok, komut reddedildi.")
+		return
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	s, err := h.stateManager.Get(opCtx, cmd.CallID)
+	if err != nil || s == nil {
+		l.Warn().Str("event", "TRANSFER_CALL_NOT_FOUND").Err(err).Msg("Devir komutu için çağrı durumu bulunamadı.")
+		return
+	}
+	if event.TenantId != s.TenantID {
+		l.Warn().Str("event", "TRANSFER_TENANT_MISMATCH").Str("tenant_id", event.TenantId).Msg("Devir komutu çağrının tenant'ına ait değil.")
+		return
+	}
+
+	switch constants.EventType(event.EventType) {
+	case constants.EventTypeTransferRequested:
+		req := transferRequest{Mode: strings.ToUpper(cmd.Mode), TargetURI: cmd.TargetURI, AgentID: cmd.AgentID}
+		if req.Mode != transferModeAttended {
+			req.Mode = transferModeBlind
+		}
+		if req.TargetURI == "" && req.AgentID != "" {
+			req.TargetURI = h.agentTargetURI(req.AgentID)
+		}
+		if req.TargetURI == "" {
+			l.Warn().Str("event", "TRANSFER_TARGET_MISSING").Msg("Devir isteğinde hedef yok.")
+			return
+		}
+		h.startTransfer(opCtx, s, req)
+	case constants.EventTypeTransferComplete:
+		h.completeTransfer(opCtx, cmd.CallID)
+	case constants.EventTypeTransferCancel:
+		h.cancelTransfer(opCtx, cmd.CallID)
+	case constants.EventTypeTransferDeclined:
+		if s.Transfer != nil {
+			h.declineTransfer(opCtx, cmd.CallID, s.Transfer.ConsultCallID, "", cmd.Reason)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	eventv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/event/v1"
+	userv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/user/v1"
+)
+
+// HandleUserIdentified, user-service'in çağrı için tanıdığı kullanıcıyı CallState'e işler
+// ve kullanıcının tercih ettiği dil farklıysa çağrının dilini değiştirir.
+func (h *CallHandler) HandleUserIdentified(ctx context.Context, event *eventv1.UserIdentifiedForCallEvent) {
+	l := h.log.With().Str("call_id", event.CallId).Logger()
+
+	user := h.identifiedUser(event.GetUser())
+	if user == nil {
+		l.Warn().Str("event", "USER_IDENTIFIED_EMPTY").Msg("Kullanıcı bilgisi olmadan user.identified.for_call alındı.")
+		return
+	}
+
+	// Olaylar eşzamanlı işlendiğinden durum Update ile (WATCH) okunup yazılır; call.started vb. ile yarışmaz.
+	var previousLang string
+	s, err := h.stateManager.Update(ctx, event.CallId, func(cs *state.CallState) error {
+		previousLang = cs.LanguageCode
+		attachUser(cs, user)
+		return nil
+	})
+	if errors.Is(err, state.ErrStateNotFound) {
+		l.Warn().Str("event", "CALL_STATE_NOT_FOUND").Msg("Tanınan kullanıcı için aktif çağrı durumu bulunamadı.")
+		return
+	}
+	if err != nil {
+		l.Error().Str("event", "CALL_STATE_SAVE_FAILED").Err(err).Msg("Tanınan kullanıcı çağrı durumuna yazılamadı.")
+		return
+	}
+	h.logLanguageSwitch(s, previousLang)
+
+	l.Info().Str("event", "USER_ATTACHED_TO_CALL").
+		Str("user_id", user.ID).
+		Str("vip_tier", user.VIPTier).
+		Str("language", s.LanguageCode).
+		Msg("👤 Tanınan kullanıcı çağrıya bağlandı.")
+}
+
+// attachUser, kullanıcıyı duruma bağlar ve tercih edilen dil farklıysa LanguageCode'u günceller.
+func attachUser(s *state.CallState, user *state.IdentifiedUser) {
+	s.User = user
+	if user.PreferredLanguage != "" {
+		s.LanguageCode = user.PreferredLanguage
+	}
+}
+
+func (h *CallHandler) logLanguageSwitch(s *state.CallState, previousLang string) {
+	if previousLang == s.LanguageCode {
+		return
+	}
+	h.log.Info().Str("event", "CALL_LANGUAGE_SWITCHED").
+		Str("call_id", s.CallID).
+		Str("from", previousLang).
+		Str("to", s.LanguageCode).
+		Bool("pipeline_active", s.PipelineActive).
+		Msg("🌐 Kullanıcının tercih ettiği dile geçiliyor.")
+}
+
+// identifiedUser, kontrat modelini CallState özetine dönüştürür. Kontratta ayrı bir VIP alanı bulunmadığından
+// VIP seviyesi, user_type değeri AGENT_VIP_USER_TYPES listesinde birebir yer alan kullanıcılara verilir.
+func (h *CallHandler) identifiedUser(u *userv1.User) *state.IdentifiedUser {
+	if u == nil || u.Id == "" {
+		return nil
+	}
+
+	user := &state.IdentifiedUser{
+		ID:                u.Id,
+		Name:              u.GetName(),
+		PreferredLanguage: u.GetPreferredLanguageCode(),
+	}
+	userType := strings.ToLower(strings.TrimSpace(u.UserType))
+	if userType != "" && slices.Contains(h.cfg.VIPUserTypes, userType) {
+		user.VIPTier = strings.ToUpper(userType)
+	}
+	return user
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/queuestats"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+	telephonyv1 "github.com/sentiric/sentiric-contracts/gen/go/sentiric/telephony/v1"
+)
+
+// voicemailObjectPath, sesli mesaj kaydının bucket içindeki yoludur.
+func voicemailObjectPath(tenantID, callID string) string {
+	return "voicemails/" + tenantID + "/" + callID + ".wav"
+}
+
+// handleVoicemail, arayana karşılama anonsunu çalar, mesajını kaydeder ve çağrıyı kapatır. Kayıt
+// TelephonyAction.StartRecording/StopRecording ile alınır; dosya ancak call.recording.available geldiğinde hazır
+// olduğundan sesli mesaja o olayla bağlanır (bkz. attachVoicemail).
+func (h *CallHandler) handleVoicemail(s *state.CallState, actionData map[string]string) {
+	greeting := constants.AnnounceVoicemailGreeting
+	if id := actionData["voicemail_announcement_id"]; id != "" {
+		greeting = constants.AnnouncementID(id)
+	}
+	maxDuration := time.Duration(h.cfg.VoicemailMaxSeconds) * time.Second
+	if v, err := strconv.Atoi(actionData["voicemail_max_seconds"]); err == nil && v > 0 {
+		maxDuration = time.Duration(v) * time.Second
+	}
+
+	callCtx, release := h.callScopes.begin(context.Background(), s.CallID)
+	go func() {
+		defer release()
+		l := h.log.With().Str("call_id", s.CallID).Logger()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		// Sesli mesaja düşen arayan kuyrukta beklemeye devam etmemeli.
+		if e := h.leaveQueue(ctx, s.CallID); e != nil {
+			h.recordQueueExit(ctx, s, queuestats.ExitVoicemail)
+		}
+		_ = h.playAnnouncementAndWait(ctx, s, greeting)
+
+		queueID := actionData["queue_id"]
+		if s.Queue != nil {
+			queueID = s.Queue.QueueID
+		}
+		bucket, uri := database.NormalizeRecordingURI(h.cfg.BucketName, voicemailObjectPath(s.TenantID, s.CallID))
+		err := database.StartVoicemail(ctx, h.db, database.Voicemail{
+			CallID:       s.CallID,
+			TenantID:     s.TenantID,
+			QueueID:      queueID,
+			CallerURI:    s.FromURI,
+			Bucket:       bucket,
+			RecordingURI: uri,
+		})
+		if err != nil {
+			l.Error().Str("event", "DB_VOICEMAIL_START_FAILED").Err(err).Msg("❌ Sesli mesaj kaydı açılamadı, çağrı sonlandırılıyor.")
+			h.compensate(context.Background(), s.CallID, "VOICEMAIL_UNAVAILABLE")
+			return
+		}
+
+		s.CurrentState = constants.StateVoicemail
+		_ = h.stateManager.Set(ctx, s)
+
+		h.recordVoicemail(callCtx, s, uri, maxDuration)
+		if callCtx.Err() == nil {
+			h.compensate(context.Background(), s.CallID, "NORMAL_CLEARING")
+		}
+	}()
+}
+
+// recordVoicemail, arayanın sesini azami süre dolana veya arayan kapatana (callCtx iptal edilene) kadar uri'ye
+// kaydeder ve kaydı durdurur.
+func (h *CallHandler) recordVoicemail(callCtx context.Context, s *state.CallState, uri string, maxDuration time.Duration) {
+	l := h.log.With().Str("call_id", s.CallID).Logger()
+
+	rpcCtx := func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if s.TenantID != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", s.TenantID)
+		}
+		if s.TraceID != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-trace-id", s.TraceID)
+		}
+		return ctx, cancel
+	}
+
+	startCtx, cancel := rpcCtx()
+	resp, err := h.clients.TelephonyAction.StartRecording(startCtx, &telephonyv1.StartRecordingRequest{
+		CallId:    s.CallID,
+		OutputUri: uri,
+	})
+	cancel()
+	if err == nil && !resp.GetSuccess() {
+		err = errors.New("kayıt servis tarafından başlatılmadı")
+	}
+	if err != nil {
+		l.Error().Str("event", "VOICEMAIL_RECORD_START_FAILED").Err(err).Msg("❌ Sesli mesaj kaydı başlatılamadı.")
+		return
+	}
+	l.Info().Str("event", "VOICEMAIL_RECORDING").Str("recording_uri", uri).Dur("max_duration", maxDuration).Msg("📼 Sesli mesaj kaydediliyor.")
+
+	timer := time.NewTimer(maxDuration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-callCtx.Done():
+	}
+
+	stopCtx, cancel := rpcCtx()
+	defer cancel()
+	if _, err := h.clients.TelephonyAction.StopRecording(stopCtx, &telephonyv1.StopRecordingRequest{CallId: s.CallID}); err != nil {
+		// Kayıt dosyası kapatılamamış olabilir; call.recording.available gelirse yine bağlanır.
+		l.Warn().Str("event", "VOICEMAIL_RECORD_STOP_FAILED").Err(err).Msg("Sesli mesaj kaydı durdurulamadı.")
+		return
+	}
+	// Dosya henüz yüklenmemiş ve public_url'i bilinmiyor olabilir; sesli mesaj call.recording.available ile bağlanır.
+	l.Info().Str("event", "VOICEMAIL_RECORD_STOPPED").Str("recording_uri", uri).Msg("📼 Sesli mesaj kaydı durduruldu, kaydın hazır olması bekleniyor.")
+}
+
+// attachVoicemail, call.recording.available ile gelen kaydı, URI'si eşleşen ve kaydı bekleyen sesli mesaja bağlar ve
+// ajan gelen kutuları için agent.voicemail.created yayınlar. Eşleşen sesli mesaj yoksa hiçbir şey yapmaz.
+func (h *CallHandler) attachVoicemail(ctx context.Context, callID string, rec database.Recording) {
+	v, err := database.AttachVoicemailRecording(ctx, h.db, callID, rec)
+	if err != nil {
+		h.log.Error().Str("event", "DB_VOICEMAIL_SAVE_FAILED").Str("call_id", callID).Err(err).Msg("Sesli mesaj kaydı bağlanamadı.")
+		return
+	}
+	if v == nil {
+		return
+	}
+
+	h.log.Info().Str("event", "VOICEMAIL_CREATED").Str("call_id", callID).Int64("voicemail_id", v.ID).Str("queue_id", v.QueueID).Msg("📬 Yeni sesli mesaj.")
+	s := &state.CallState{CallID: v.CallID, TraceID: v.CallID, TenantID: v.TenantID}
+	h.publishGenericEvent(ctx, constants.EventTypeVoicemailCreated, s, map[string]interface{}{
+		"voicemailId":  v.ID,
+		"callId":       v.CallID,
+		"tenantId":     v.TenantID,
+		"queueId":      v.QueueID,
+		"callerUri":    v.CallerURI,
+		"bucket":       v.Bucket,
+		"recordingUri": v.RecordingURI,
+		"publicUrl":    v.PublicURL,
+		"createdAt":    v.CreatedAt,
+	})
+}
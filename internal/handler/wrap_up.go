@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	workspacev1 "github.com/sentiric/sentiric-agent-service/gen/go/sentiric/agent/v1"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// wrapUpDuration, tenant'ın wrap-up süresini döner; tenant'a özel ayar yoksa (veya okunamazsa) varsayılan kullanılır.
+func (h *CallHandler) wrapUpDuration(ctx context.Context, tenantID string) time.Duration {
+	seconds := h.cfg.WrapUpSeconds
+	if v, ok, err := database.GetWrapUpSeconds(ctx, h.db, tenantID); err != nil {
+		h.log.Warn().Str("event", "WRAP_UP_SETTINGS_READ_FAILED").Str("tenant_id", tenantID).Err(err).Msg("Wrap-up süresi okunamadı, varsayılan kullanılıyor.")
+	} else if ok {
+		seconds = v
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// startWrapUp, görüşmesi biten ajanı doğrudan ONLINE yapmak yerine wrap-up'a alır. Süre sıfırsa ajan hemen serbest kalır.
+func (h *CallHandler) startWrapUp(ctx context.Context, tenantID, agentID, callID string) {
+	if agentID == "" {
+		return
+	}
+	d := h.wrapUpDuration(ctx, tenantID)
+	if d <= 0 {
+		h.releaseAgent(ctx, tenantID, agentID)
+		return
+	}
+
+	l := h.log.With().Str("agent_id", agentID).Str("call_id", callID).Logger()
+	started, err := h.presence.BeginWrapUp(ctx, tenantID, agentID, callID, d)
+	if err != nil {
+		l.Warn().Str("event", "WRAP_UP_START_FAILED").Err(err).Msg("Wrap-up başlatılamadı, ajan serbest bırakılıyor.")
+		h.releaseAgent(ctx, tenantID, agentID)
+		return
+	}
+	if !started {
+		return
+	}
+	l.Info().Str("event", "WRAP_UP_STARTED").Dur("duration", d).Msg("📝 Ajan görüşme sonrası çalışmaya (wrap-up) alındı.")
+	h.publishWrapUp(ctx, constants.EventTypeWrapUpStarted, tenantID, callID, map[string]interface{}{
+		"agentId":         agentID,
+		"durationSeconds": int(d.Seconds()),
+	})
+}
+
+// expireWrapUps, süresi dolan wrap-up'ları sonlandırıp ajanları tekrar ONLINE yapar. Dağıtıcı her turda çağırır;
+// süreler Redis'te tutulduğundan replika yeniden başlasa da ajan wrap-up'ta takılı kalmaz.
+func (h *CallHandler) expireWrapUps(ctx context.Context) {
+	due, err := h.presence.DueWrapUps(ctx, time.Now())
+	if err != nil {
+		return
+	}
+	for _, w := range due {
+		if ended, err := h.presence.EndWrapUp(ctx, w.TenantID, w.AgentID, ""); err == nil && ended {
+			h.log.Info().Str("event", "WRAP_UP_EXPIRED").Str("tenant_id", w.TenantID).Str("agent_id", w.AgentID).Msg("Wrap-up süresi doldu, ajan ONLINE.")
+		}
+	}
+}
+
+// SubmitWrapUp, ajanın sonuç kodunu ve notunu konuşmaya yazar ve (aksi istenmedikçe) wrap-up'ı bitirir.
+func (h *CallHandler) SubmitWrapUp(ctx context.Context, req *workspacev1.SubmitWrapUpRequest) (*workspacev1.SubmitWrapUpResponse, error) {
+	agent, err := authorizeAgent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.CallId == "" || req.DispositionCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "call_id ve disposition_code zorunludur")
+	}
+	l := h.log.With().Str("agent_id", agent.AgentID).Str("call_id", req.CallId).Logger()
+
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := database.ValidateDisposition(dbCtx, h.db, agent.TenantID, req.DispositionCode); err != nil {
+		if errors.Is(err, database.ErrUnknownDisposition) {
+			return &workspacev1.SubmitWrapUpResponse{Accepted: false, ErrorMessage: "tanımsız sonuç kodu: " + req.DispositionCode}, nil
+		}
+		l.Error().Str("event", "DB_DISPOSITION_READ_FAILED").Err(err).Msg("Sonuç kodları okunamadı.")
+		return nil, status.Error(codes.Internal, "sonuç kodları okunamadı")
+	}
+
+	err = database.SaveDisposition(dbCtx, h.db, req.CallId, agent.TenantID, database.Disposition{
+		AgentID: agent.AgentID,
+		Code:    req.DispositionCode,
+		Notes:   req.Notes,
+	})
+	if errors.Is(err, database.ErrConversationNotFound) {
+		return nil, status.Error(codes.NotFound, "konuşma bulunamadı")
+	}
+	if errors.Is(err, database.ErrNotHandlingAgent) {
+		l.Warn().Str("event", "WRAP_UP_AGENT_MISMATCH").Msg("⛔ Sonuç, görüşmeyi yürütmeyen bir ajandan geldi.")
+		return nil, status.Error(codes.PermissionDenied, "görüşme bu ajana ait değil")
+	}
+	if err != nil {
+		l.Error().Str("event", "DB_DISPOSITION_SAVE_FAILED").Err(err).Msg("Sonuç kodu kaydedilemedi.")
+		return nil, status.Error(codes.Internal, "sonuç kaydedilemedi")
+	}
+
+	online := false
+	if !req.KeepWrapUp {
+		online, err = h.presence.EndWrapUp(dbCtx, agent.TenantID, agent.AgentID, req.CallId)
+		if err != nil {
+			l.Warn().Str("event", "WRAP_UP_END_FAILED").Err(err).Msg("Wrap-up sonlandırılamadı; süre dolunca ajan ONLINE olacak.")
+		}
+	}
+
+	l.Info().Str("event", "WRAP_UP_SUBMITTED").Str("disposition_code", req.DispositionCode).Bool("online", online).Msg("✅ Görüşme sonucu kaydedildi.")
+	h.publishWrapUp(dbCtx, constants.EventTypeWrapUpCompleted, agent.TenantID, req.CallId, map[string]interface{}{
+		"agentId":         agent.AgentID,
+		"dispositionCode": req.DispositionCode,
+		"notes":           req.Notes,
+	})
+	return &workspacev1.SubmitWrapUpResponse{Accepted: true, Online: online}, nil
+}
+
+// assignCallAgent, çağrıya bağlanan ajanı konuşmaya yazar; wrap-up sonucu yalnızca bu ajandan kabul edilir.
+func (h *CallHandler) assignCallAgent(ctx context.Context, s *state.CallState, agentID string) {
+	if agentID == "" {
+		return
+	}
+	dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := database.AssignConversationAgent(dbCtx, h.db, s.CallID, s.TenantID, agentID); err != nil {
+		h.log.Warn().Str("event", "DB_CONVERSATION_AGENT_SAVE_FAILED").Str("call_id", s.CallID).Str("agent_id", agentID).Err(err).Msg("Görüşmeyi yürüten ajan kaydedilemedi.")
+	}
+}
+
+func (h *CallHandler) publishWrapUp(ctx context.Context, eventType constants.EventType, tenantID, callID string, payload map[string]interface{}) {
+	payload["callId"] = callID
+	payload["tenantId"] = tenantID
+	h.publishGenericEvent(ctx, eventType, &state.CallState{CallID: callID, TraceID: callID, TenantID: tenantID}, payload)
+}
@@ -29,6 +29,110 @@ var (
 		},
 		[]string{"event_type", "reason"},
 	)
+	// EventsUnrouted, kayıtlı bir işleyicisi olmayan olay türlerini sayar.
+	EventsUnrouted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sentiric_agent_events_unrouted_total",
+			Help: "İşleyicisi kayıtlı olmadığı için yoksayılan toplam olay sayısı.",
+		},
+		[]string{"event_type"},
+	)
+	// EventsDuplicateSkipped, idempotency katmanının atladığı yeniden teslimatları sayar.
+	EventsDuplicateSkipped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sentiric_agent_events_duplicate_skipped_total",
+			Help: "Daha önce işlendiği için atlanan toplam olay sayısı.",
+		},
+		[]string{"event_type"},
+	)
+	// DialogPhaseDuration, botun her diyalog fazında (LISTENING/THINKING/SPEAKING...) geçirdiği süreyi ölçer.
+	DialogPhaseDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "sentiric_agent_dialog_phase_duration_seconds",
+			Help:    "Pipeline'ın bir diyalog fazında kaldığı süre.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+		[]string{"phase"},
+	)
+	// PipelineRestarts, arıza sonrası yedek modellerle yeniden başlatılan pipeline sayısını tutar.
+	PipelineRestarts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sentiric_agent_pipeline_restarts_total",
+			Help: "Arıza sonrası yeniden başlatılan toplam TAS pipeline sayısı.",
+		},
+		[]string{"component"},
+	)
+	// MigrationFailures, açılışta başarısız olan migration denemelerini sayar; artmaya devam ediyorsa servis
+	// trafik almaya başlamamıştır.
+	MigrationFailures = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sentiric_agent_migration_failures_total",
+			Help: "Başarısız veritabanı migration denemelerinin toplam sayısı.",
+		},
+	)
+	// TranscriptsDropped, tampon dolduğu veya veritabanı yazımı başarısız olduğu için kaybedilen ifadeleri sayar.
+	TranscriptsDropped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "sentiric_agent_transcripts_dropped_total",
+			Help: "Veritabanına yazılamadan düşürülen toplam transcript ifadesi sayısı.",
+		},
+	)
+	// BridgeOutcomes, B2BUA üzerinden kurulan köprülerin sonuçlarını sayar (ANSWERED, BUSY, NO_ANSWER, FAILED).
+	BridgeOutcomes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sentiric_agent_bridge_outcomes_total",
+			Help: "Sonucuna göre toplam köprü (bridge) denemesi sayısı.",
+		},
+		[]string{"outcome"},
+	)
+	// QueueWaiting, kuyrukta bekleyen üye sayısıdır.
+	QueueWaiting = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sentiric_agent_queue_waiting",
+			Help: "Kuyrukta bekleyen çağrı ve geri arama sayısı.",
+		},
+		[]string{"tenant_id", "queue_id"},
+	)
+	// QueueLongestWait, kuyrukta en uzun bekleyen üyenin bekleme süresidir.
+	QueueLongestWait = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sentiric_agent_queue_longest_wait_seconds",
+			Help: "Kuyrukta en uzun bekleyen üyenin bekleme süresi.",
+		},
+		[]string{"tenant_id", "queue_id"},
+	)
+	// QueueASA, içinde bulunulan 15 dakikalık aralıktaki ortalama cevaplanma süresidir.
+	QueueASA = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sentiric_agent_queue_asa_seconds",
+			Help: "Aralıktaki ortalama cevaplanma süresi (ASA).",
+		},
+		[]string{"tenant_id", "queue_id"},
+	)
+	// QueueAbandonRate, içinde bulunulan aralıkta kuyrukta beklerken kapatan arayanların oranıdır.
+	QueueAbandonRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sentiric_agent_queue_abandon_ratio",
+			Help: "Aralıkta kuyruğa giren çağrılardan terk edilenlerin oranı (0-1).",
+		},
+		[]string{"tenant_id", "queue_id"},
+	)
+	// QueueServiceLevel, içinde bulunulan aralıkta hizmet seviyesi eşiği içinde cevaplanan çağrıların oranıdır.
+	QueueServiceLevel = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sentiric_agent_queue_service_level_ratio",
+			Help: "Aralıkta eşik içinde cevaplanan çağrıların oranı (0-1).",
+		},
+		[]string{"tenant_id", "queue_id"},
+	)
+	// AgentsByStatus, tenant'ın bağlı ajanlarının durumlarına göre sayısıdır.
+	AgentsByStatus = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "sentiric_agent_agents",
+			Help: "Duruma göre bağlı ajan sayısı.",
+		},
+		[]string{"tenant_id", "status"},
+	)
 )
 
 // StartServer, metrikleri sunmak için bir HTTP sunucusu başlatır.
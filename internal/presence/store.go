@@ -0,0 +1,286 @@
+// Package presence, ajanların anlık durumlarını (OFFLINE/ONLINE/BUSY/WRAP_UP/MISSED/BREAK) Redis'te TTL ile tutar
+// ve boştaki ajanları en uzun süredir bekleyenden başlayarak sıralı tutar; seçilen ajan atomik olarak sahiplenilir.
+package presence
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Ajan durumları.
+const (
+	StatusOffline = "OFFLINE"
+	StatusOnline  = "ONLINE"
+	StatusBusy    = "BUSY"
+	StatusWrapUp  = "WRAP_UP"
+	StatusBreak   = "BREAK"
+	// StatusMissed, teklifi cevapsız bırakan ajanın yardımcı durumudur; ajan ONLINE'a dönene kadar çağrı almaz.
+	StatusMissed = "MISSED"
+)
+
+// Agent, bir ajanın anlık durumudur.
+type Agent struct {
+	AgentID  string
+	TenantID string
+	Status   string
+	Since    time.Time
+	// SessionID, ajan masaüstü oturumu açıksa oturum kimliğidir; boşsa ajan yalnızca olaylarla yönetilir.
+	SessionID string
+}
+
+type Store struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+// NewStore, durumları verilen TTL ile tutan bir depo oluşturur. Ajan bağlantısı düşüp
+// durum yenilenmezse kayıt düşer ve ajan OFFLINE sayılır.
+func NewStore(rdb *redis.Client, ttl time.Duration) *Store {
+	return &Store{rdb: rdb, ttl: ttl}
+}
+
+func agentKey(tenantID, agentID string) string { return "presence:" + tenantID + ":" + agentID }
+func idleKey(tenantID string) string           { return "presence:idle:" + tenantID }
+
+// wrapUpKey, süresi dolacak wrap-up'ları "tenant|agent" üyesi ve bitiş zamanı skoruyla tutar.
+const wrapUpKey = "presence:wrapup"
+
+func wrapUpMember(tenantID, agentID string) string { return tenantID + "|" + agentID }
+
+// rosterKey, tenant'ın bağlı ajanlarını tutar; durum sayımları (bkz. CountByStatus) bu küme üzerinden yapılır.
+func rosterKey(tenantID string) string { return "presence:agents:" + tenantID }
+
+// tenantsKey, bağlı ajanı olmuş tenant'ları tutar.
+const tenantsKey = "presence:tenants"
+
+// SetStatus, ajanın durumunu yazar. ONLINE ajanlar boşta kalma sırasına girer, diğerleri çıkar.
+func (s *Store) SetStatus(ctx context.Context, tenantID, agentID, status string) error {
+	now := time.Now()
+	key := agentKey(tenantID, agentID)
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if status == StatusOffline {
+			pipe.Del(ctx, key)
+			pipe.ZRem(ctx, idleKey(tenantID), agentID)
+			pipe.SRem(ctx, rosterKey(tenantID), agentID)
+			return nil
+		}
+		pipe.HSet(ctx, key, "status", status, "since", now.UnixMilli())
+		pipe.SAdd(ctx, rosterKey(tenantID), agentID)
+		pipe.SAdd(ctx, tenantsKey, tenantID)
+		pipe.Expire(ctx, key, s.ttl)
+		if status == StatusOnline {
+			pipe.ZAdd(ctx, idleKey(tenantID), &redis.Z{Score: float64(now.UnixMilli()), Member: agentID})
+		} else {
+			pipe.ZRem(ctx, idleKey(tenantID), agentID)
+		}
+		return nil
+	})
+	return err
+}
+
+// StartSession, ajan masaüstü oturumunu açar ve ajanı verilen duruma alır.
+func (s *Store) StartSession(ctx context.Context, tenantID, agentID, sessionID, status string) error {
+	if err := s.SetStatus(ctx, tenantID, agentID, status); err != nil {
+		return err
+	}
+	return s.rdb.HSet(ctx, agentKey(tenantID, agentID), "session_id", sessionID).Err()
+}
+
+// Touch, ajan bağlı kaldıkça kaydın süresini uzatır.
+func (s *Store) Touch(ctx context.Context, tenantID, agentID string) error {
+	return s.rdb.Expire(ctx, agentKey(tenantID, agentID), s.ttl).Err()
+}
+
+// Get, ajanın durumunu döner; kayıt yoksa ajan OFFLINE kabul edilir.
+func (s *Store) Get(ctx context.Context, tenantID, agentID string) (*Agent, error) {
+	vals, err := s.rdb.HGetAll(ctx, agentKey(tenantID, agentID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	a := &Agent{AgentID: agentID, TenantID: tenantID, Status: StatusOffline}
+	if st := vals["status"]; st != "" {
+		a.Status = st
+		a.SessionID = vals["session_id"]
+		if ms, err := strconv.ParseInt(vals["since"], 10, 64); err == nil {
+			a.Since = time.UnixMilli(ms)
+		}
+	}
+	return a, nil
+}
+
+// Tenants, bağlı ajanı olmuş tenant'ları döner.
+func (s *Store) Tenants(ctx context.Context) ([]string, error) {
+	return s.rdb.SMembers(ctx, tenantsKey).Result()
+}
+
+// CountByStatus, tenant'ın bağlı ajanlarını durumlarına göre sayar. Kaydı düşmüş (TTL) ajanlar
+// OFFLINE'a geçmiş sayılır ve kümeden temizlenir.
+func (s *Store) CountByStatus(ctx context.Context, tenantID string) (map[string]int, error) {
+	agents, err := s.rdb.SMembers(ctx, rosterKey(tenantID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	if len(agents) == 0 {
+		return counts, nil
+	}
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(agents))
+	for i, agentID := range agents {
+		cmds[i] = pipe.HGet(ctx, agentKey(tenantID, agentID), "status")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	var gone []interface{}
+	for i, cmd := range cmds {
+		st, err := cmd.Result()
+		if errors.Is(err, redis.Nil) {
+			gone = append(gone, agents[i])
+			continue
+		}
+		counts[st]++
+	}
+	if len(gone) > 0 {
+		pruneRosterScript.Run(ctx, s.rdb, []string{rosterKey(tenantID)}, append([]interface{}{"presence:" + tenantID + ":"}, gone...)...)
+	}
+	return counts, nil
+}
+
+// pruneRosterScript, kaydı hâlâ olmayan ajanları kümeden çıkarır; sayım sırasında yeniden bağlanan ajan silinmez.
+var pruneRosterScript = redis.NewScript(`
+for i = 2, #ARGV do
+	if redis.call("EXISTS", ARGV[1] .. ARGV[i]) == 0 then
+		redis.call("SREM", KEYS[1], ARGV[i])
+	end
+end
+return 0`)
+
+// claimScript, ajan hâlâ ONLINE ise onu BUSY yapıp boşta sırasından çıkarır. Kaydı düşmüş (TTL) ajan
+// sıradan temizlenir.
+var claimScript = redis.NewScript(`
+local status = redis.call("HGET", KEYS[2], "status")
+if not status then
+	redis.call("ZREM", KEYS[1], ARGV[1])
+	return 0
+end
+if status ~= "ONLINE" then
+	return 0
+end
+redis.call("HSET", KEYS[2], "status", "BUSY", "since", ARGV[2])
+redis.call("ZREM", KEYS[1], ARGV[1])
+return 1`)
+
+// IdleAgents, boşta sırasındaki en fazla limit ajanı en uzun süredir bekleyenden başlayarak döner.
+// Liste anlık görüntüdür; ajanın hâlâ boşta olduğu Claim ile doğrulanır.
+func (s *Store) IdleAgents(ctx context.Context, tenantID string, limit int64) ([]string, error) {
+	return s.rdb.ZRange(ctx, idleKey(tenantID), 0, limit-1).Result()
+}
+
+// Claim, belirli bir ajanı ONLINE ise atomik olarak BUSY yapar.
+func (s *Store) Claim(ctx context.Context, tenantID, agentID string) (bool, error) {
+	n, err := claimScript.Run(ctx, s.rdb, []string{idleKey(tenantID), agentKey(tenantID, agentID)},
+		agentID, time.Now().UnixMilli()).Int()
+	return n == 1, err
+}
+
+// Release, görevi biten BUSY ajanı tekrar ONLINE yapar. Ajan bu arada başka bir duruma
+// (BREAK, OFFLINE) geçtiyse dokunulmaz.
+func (s *Store) Release(ctx context.Context, tenantID, agentID string) error {
+	return s.ReleaseTo(ctx, tenantID, agentID, StatusOnline)
+}
+
+// ReleaseTo, BUSY ajanı verilen duruma geçirir (ör. cevapsız teklif sonrası MISSED).
+func (s *Store) ReleaseTo(ctx context.Context, tenantID, agentID, status string) error {
+	a, err := s.Get(ctx, tenantID, agentID)
+	if err != nil || a.Status != StatusBusy {
+		return err
+	}
+	return s.SetStatus(ctx, tenantID, agentID, status)
+}
+
+func missesKey(tenantID, agentID string) string { return "presence:misses:" + tenantID + ":" + agentID }
+
+// RecordMiss, ajanın art arda cevapsız bıraktığı teklif sayısını artırıp döner.
+func (s *Store) RecordMiss(ctx context.Context, tenantID, agentID string) (int64, error) {
+	key := missesKey(tenantID, agentID)
+	incr := s.rdb.Incr(ctx, key)
+	s.rdb.Expire(ctx, key, 24*time.Hour)
+	return incr.Result()
+}
+
+// ResetMisses, ajan bir teklifi cevapladığında sayacı sıfırlar.
+func (s *Store) ResetMisses(ctx context.Context, tenantID, agentID string) error {
+	return s.rdb.Del(ctx, missesKey(tenantID, agentID)).Err()
+}
+
+// BeginWrapUp, görüşmesi biten BUSY ajanı verilen süre için WRAP_UP yapar. Ajan bu arada başka
+// bir duruma geçtiyse dokunulmaz ve false döner.
+func (s *Store) BeginWrapUp(ctx context.Context, tenantID, agentID, callID string, d time.Duration) (bool, error) {
+	now := time.Now()
+	res, err := beginWrapUpScript.Run(ctx, s.rdb, []string{agentKey(tenantID, agentID), wrapUpKey},
+		callID, now.UnixMilli(), now.Add(d).UnixMilli(), wrapUpMember(tenantID, agentID), int(s.ttl.Seconds())).Int()
+	return res == 1, err
+}
+
+var beginWrapUpScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "status") ~= "BUSY" then
+	return 0
+end
+redis.call("HSET", KEYS[1], "status", "WRAP_UP", "since", ARGV[2], "call_id", ARGV[1])
+redis.call("EXPIRE", KEYS[1], ARGV[5])
+redis.call("ZADD", KEYS[2], ARGV[3], ARGV[4])
+return 1`)
+
+// EndWrapUp, WRAP_UP durumundaki ajanı ONLINE yapıp boşta sırasına alır. callID boş değilse yalnızca
+// o çağrının wrap-up'ı sonlandırılır.
+func (s *Store) EndWrapUp(ctx context.Context, tenantID, agentID, callID string) (bool, error) {
+	res, err := endWrapUpScript.Run(ctx, s.rdb, []string{agentKey(tenantID, agentID), wrapUpKey, idleKey(tenantID)},
+		callID, time.Now().UnixMilli(), wrapUpMember(tenantID, agentID), agentID).Int()
+	return res == 1, err
+}
+
+// Başka bir çağrının wrap-up'ı sürüyorsa zamanlayıcısına dokunulmaz; ajan wrap-up'ta değilse zamanlayıcı
+// sahipsiz kaldığından temizlenir.
+var endWrapUpScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "status") ~= "WRAP_UP" then
+	redis.call("ZREM", KEYS[2], ARGV[3])
+	return 0
+end
+if ARGV[1] ~= "" and redis.call("HGET", KEYS[1], "call_id") ~= ARGV[1] then
+	return 0
+end
+redis.call("ZREM", KEYS[2], ARGV[3])
+redis.call("HSET", KEYS[1], "status", "ONLINE", "since", ARGV[2])
+redis.call("HDEL", KEYS[1], "call_id")
+redis.call("ZADD", KEYS[3], ARGV[2], ARGV[4])
+return 1`)
+
+// WrapUp, süresi dolmuş bir wrap-up'ı tanımlar.
+type WrapUp struct {
+	TenantID string
+	AgentID  string
+}
+
+// DueWrapUps, bitiş zamanı geçmiş wrap-up'ları döner.
+func (s *Store) DueWrapUps(ctx context.Context, now time.Time) ([]WrapUp, error) {
+	members, err := s.rdb.ZRangeByScore(ctx, wrapUpKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	due := make([]WrapUp, 0, len(members))
+	for _, m := range members {
+		if tenantID, agentID, ok := strings.Cut(m, "|"); ok {
+			due = append(due, WrapUp{TenantID: tenantID, AgentID: agentID})
+		}
+	}
+	return due, nil
+}
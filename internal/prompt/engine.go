@@ -0,0 +1,138 @@
+// Package prompt, veritabanındaki prompt şablonlarını çağrı bağlamıyla doldurarak
+// pipeline'a gönderilecek sistem ve karşılama metinlerini üretir.
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sentiric/sentiric-agent-service/internal/catalog"
+	"github.com/sentiric/sentiric-agent-service/internal/constants"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+// Şablonlarda kullanılabilen değişkenler ({{.caller_number}} biçiminde).
+const (
+	VarCallerNumber = "caller_number"
+	VarUserName     = "user_name"
+	VarTenantID     = "tenant_id"
+	VarLanguageCode = "language_code"
+	VarTimeOfDay    = "time_of_day"
+)
+
+// ErrMissingVariable, şablon çağrı bağlamında değeri olmayan bir değişken kullandığında döner.
+var ErrMissingVariable = errors.New("template variable missing")
+
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]+)"`)
+
+// Locator, tenant'ın saat dilimini çözer; tenant için saat dilimi bilinmiyorsa ok=false döner.
+type Locator interface {
+	Location(ctx context.Context, tenantID string) (*time.Location, bool)
+}
+
+type Engine struct {
+	catalog  *catalog.Catalog
+	locator  Locator
+	fallback *time.Location
+}
+
+// NewEngine, time_of_day değişkenini tenant'ın saat dilimine göre hesaplayan bir motor oluşturur.
+// Tenant'ın saat dilimi bilinmiyorsa fallbackTimezone, o da yüklenemezse UTC kullanılır.
+func NewEngine(catalog *catalog.Catalog, locator Locator, fallbackTimezone string) *Engine {
+	loc, err := time.LoadLocation(fallbackTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &Engine{catalog: catalog, locator: locator, fallback: loc}
+}
+
+// Render, şablonu tenant/dil/sistem sırasıyla yükler ve CallState'ten türetilen değişkenlerle doldurur.
+func (e *Engine) Render(ctx context.Context, templateID constants.TemplateID, s *state.CallState) (string, error) {
+	content, err := e.catalog.TemplateContent(ctx, templateID, s.TenantID, s.LanguageCode)
+	if err != nil {
+		return "", fmt.Errorf("şablon yüklenemedi (%s): %w", templateID, err)
+	}
+	return Execute(string(templateID), content, e.Variables(ctx, s))
+}
+
+// Variables, çağrı bağlamından şablon değişkenlerini üretir. Değeri boş olan değişkenler
+// haritaya eklenmez; böylece şablonda kullanılmaları eksik değişken hatası verir.
+func (e *Engine) Variables(ctx context.Context, s *state.CallState) map[string]string {
+	vars := map[string]string{
+		VarCallerNumber: CallerNumber(s.FromURI),
+		VarTenantID:     s.TenantID,
+		VarLanguageCode: s.LanguageCode,
+		VarTimeOfDay:    TimeOfDay(time.Now().In(e.tenantLocation(ctx, s.TenantID))),
+	}
+	if s.User != nil {
+		vars[VarUserName] = s.User.Name
+	}
+	for k, v := range vars {
+		if v == "" {
+			delete(vars, k)
+		}
+	}
+	return vars
+}
+
+func (e *Engine) tenantLocation(ctx context.Context, tenantID string) *time.Location {
+	if e.locator != nil && tenantID != "" {
+		if loc, ok := e.locator.Location(ctx, tenantID); ok {
+			return loc
+		}
+	}
+	return e.fallback
+}
+
+// Execute, şablonu verilen değişkenlerle işler. Eksik değişkenler ErrMissingVariable ile sarılır.
+func Execute(name, content string, vars map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("şablon ayrıştırılamadı (%s): %w", name, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		if m := missingKeyPattern.FindStringSubmatch(err.Error()); m != nil {
+			return "", fmt.Errorf("%w: %s (%s)", ErrMissingVariable, m[1], name)
+		}
+		return "", fmt.Errorf("şablon işlenemedi (%s): %w", name, err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// CallerNumber, SIP URI'sinden arayan numarasını çıkarır ("sip:+90555...@host" → "+90555...").
+func CallerNumber(fromURI string) string {
+	uri := strings.TrimSpace(fromURI)
+	if i := strings.Index(uri, "<"); i >= 0 {
+		uri = strings.TrimSuffix(uri[i+1:], ">")
+	}
+	uri = strings.TrimPrefix(strings.TrimPrefix(uri, "sips:"), "sip:")
+	uri = strings.TrimPrefix(uri, "tel:")
+	if user, _, ok := strings.Cut(uri, "@"); ok {
+		uri = user
+	}
+	if user, _, ok := strings.Cut(uri, ";"); ok {
+		uri = user
+	}
+	return uri
+}
+
+// TimeOfDay, saati şablonlarda kullanılan gün dilimine çevirir: morning, afternoon, evening, night.
+func TimeOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return "morning"
+	case h >= 12 && h < 18:
+		return "afternoon"
+	case h >= 18 && h < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
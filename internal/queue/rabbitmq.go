@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"time"
 
@@ -24,6 +25,49 @@ type GhostMessage struct {
 	Body        []byte
 }
 
+// Delivery, tüketilen bir AMQP mesajının yönlendirme için gereken alanlarını taşır.
+type Delivery struct {
+	RoutingKey  string
+	ContentType string
+	Type        string
+	MessageID   string
+	Headers     amqp091.Table
+	Body        []byte
+}
+
+// EventType, mesajın olay türünü çözer: önce AMQP 'type' özelliği, sonra
+// 'type' başlığı, en son routing key kullanılır.
+func (d Delivery) EventType() string {
+	if d.Type != "" {
+		return d.Type
+	}
+	if t, ok := d.Headers["type"].(string); ok && t != "" {
+		return t
+	}
+	return d.RoutingKey
+}
+
+// RejectError, mesajın yeniden denenmeden gerekçesiyle birlikte DLQ'ya
+// gönderilmesi gerektiğini belirtir.
+type RejectError struct {
+	Reason string
+	Err    error
+}
+
+func (e *RejectError) Error() string {
+	if e.Err == nil {
+		return e.Reason
+	}
+	return e.Reason + ": " + e.Err.Error()
+}
+
+func (e *RejectError) Unwrap() error { return e.Err }
+
+// Reject, verilen gerekçe ile bir RejectError oluşturur.
+func Reject(reason string, err error) error {
+	return &RejectError{Reason: reason, Err: err}
+}
+
 type RabbitMQ struct {
 	url    string
 	log    zerolog.Logger
@@ -146,7 +190,7 @@ func (m *RabbitMQ) flushBuffer(ctx context.Context) {
 	}
 }
 
-func (m *RabbitMQ) Start(ctx context.Context, handlerFunc func([]byte), wg *sync.WaitGroup) {
+func (m *RabbitMQ) Start(ctx context.Context, handlerFunc func(Delivery) error, wg *sync.WaitGroup) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -206,7 +250,7 @@ func (m *RabbitMQ) setupTopology(ch *amqp091.Channel) error {
 	return ch.QueueBind(q.Name, "#", exchangeName, false, nil)
 }
 
-func (m *RabbitMQ) consume(ctx context.Context, ch *amqp091.Channel, handlerFunc func([]byte), wg *sync.WaitGroup) {
+func (m *RabbitMQ) consume(ctx context.Context, ch *amqp091.Channel, handlerFunc func(Delivery) error, wg *sync.WaitGroup) {
 	_ = ch.Qos(10, 0, false)
 	msgs, err := ch.Consume(agentQueueName, "", false, false, false, false, nil)
 	if err != nil {
@@ -236,9 +280,60 @@ func (m *RabbitMQ) consume(ctx context.Context, ch *amqp091.Channel, handlerFunc
 					}
 				}()
 
-				handlerFunc(msg.Body)
+				err := handlerFunc(Delivery{
+					RoutingKey:  msg.RoutingKey,
+					ContentType: msg.ContentType,
+					Type:        msg.Type,
+					MessageID:   msg.MessageId,
+					Headers:     msg.Headers,
+					Body:        msg.Body,
+				})
+				if err != nil {
+					m.deadLetter(ctx, ch, msg, err)
+					return
+				}
 				_ = msg.Ack(false)
 			}(d)
 		}
 	}
 }
+
+// deadLetter, reddedilen mesajı 'x-reject-reason' başlığı ile DLX'e yayınlar ve
+// orijinalini onaylar. Yayın başarısız olursa mesaj Nack edilerek kuyruğun
+// kendi dead-letter yönlendirmesine bırakılır.
+func (m *RabbitMQ) deadLetter(ctx context.Context, ch *amqp091.Channel, msg amqp091.Delivery, cause error) {
+	reason := "handler_error"
+	var rejectErr *RejectError
+	if errors.As(cause, &rejectErr) {
+		reason = rejectErr.Reason
+	}
+
+	m.log.Warn().Str("event", "RMQ_MESSAGE_REJECTED").
+		Str("routing_key", msg.RoutingKey).
+		Str("content_type", msg.ContentType).
+		Str("reason", reason).
+		Err(cause).
+		Msg("Mesaj işlenemedi, DLQ'ya gönderiliyor.")
+
+	headers := amqp091.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers["x-reject-reason"] = reason
+	headers["x-reject-error"] = cause.Error()
+
+	err := ch.PublishWithContext(ctx, dlxName, msg.RoutingKey, false, false, amqp091.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		Type:         msg.Type,
+		MessageId:    msg.MessageId,
+		Body:         msg.Body,
+		DeliveryMode: amqp091.Persistent,
+	})
+	if err != nil {
+		m.log.Error().Str("event", "RMQ_DLQ_PUBLISH_FAIL").Err(err).Msg("DLX yayını başarısız, mesaj Nack ediliyor.")
+		_ = msg.Nack(false, false)
+		return
+	}
+	_ = msg.Ack(false)
+}
@@ -0,0 +1,152 @@
+package queuestats
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/presence"
+)
+
+// rollupLookback, özeti yazılmamış kapanmış aralıkların geriye doğru kaç aralık aranacağıdır; servis
+// kısa süre kapalı kalsa da aralıklar kaybolmaz.
+const rollupLookback = 8
+
+// reportedStatuses, ajan metriğinde her zaman yazılan durumlardır; ajanı olmayan durum 0 görünür.
+var reportedStatuses = []string{presence.StatusOnline, presence.StatusBusy, presence.StatusWrapUp, presence.StatusBreak, presence.StatusMissed}
+
+func rollupKey(start time.Time) string { return "stats:rollup:" + strconv.FormatInt(start.Unix(), 10) }
+
+// Start, göstergeleri periyodik olarak yeniler ve kapanan aralıkların özetlerini Postgres'e yazar.
+func (e *Engine) Start(ctx context.Context, wg *sync.WaitGroup) {
+	interval := e.refresh
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				opCtx, cancel := context.WithTimeout(ctx, interval)
+				e.refreshGauges(opCtx)
+				e.rollup(opCtx)
+				cancel()
+			}
+		}
+	}()
+}
+
+func (e *Engine) refreshGauges(ctx context.Context) {
+	refs, err := e.Queues(ctx, "")
+	if err != nil {
+		e.log.Warn().Str("event", "QUEUE_STATS_SCAN_FAILED").Err(err).Msg("İstatistik kuyrukları okunamadı.")
+		return
+	}
+	for _, ref := range refs {
+		snap, err := e.Snapshot(ctx, ref.TenantID, ref.QueueID)
+		if err != nil {
+			continue
+		}
+		if snap.Waiting == 0 && snap.Counters.empty() {
+			if pruned, err := e.prune(ctx, ref, snap.IntervalStart); err == nil && pruned {
+				e.deleteQueueGauges(ref)
+				continue
+			}
+		}
+		e.gauges.Waiting.WithLabelValues(ref.TenantID, ref.QueueID).Set(float64(snap.Waiting))
+		e.gauges.LongestWait.WithLabelValues(ref.TenantID, ref.QueueID).Set(snap.LongestWait.Seconds())
+		e.gauges.ASA.WithLabelValues(ref.TenantID, ref.QueueID).Set(snap.ASA().Seconds())
+		e.gauges.AbandonRate.WithLabelValues(ref.TenantID, ref.QueueID).Set(snap.AbandonRate())
+		e.gauges.ServiceLevel.WithLabelValues(ref.TenantID, ref.QueueID).Set(snap.ServiceLevel())
+	}
+
+	tenants, err := e.presence.Tenants(ctx)
+	if err != nil {
+		return
+	}
+	for _, tenantID := range tenants {
+		counts, err := e.Agents(ctx, tenantID)
+		if err != nil {
+			continue
+		}
+		for _, st := range reportedStatuses {
+			e.gauges.Agents.WithLabelValues(tenantID, st).Set(float64(counts[st]))
+		}
+	}
+}
+
+// deleteQueueGauges, listeden çıkarılan kuyruğun metrik serilerini siler.
+func (e *Engine) deleteQueueGauges(ref callqueue.Ref) {
+	for _, g := range []*prometheus.GaugeVec{e.gauges.Waiting, e.gauges.LongestWait, e.gauges.ASA, e.gauges.AbandonRate, e.gauges.ServiceLevel} {
+		g.DeleteLabelValues(ref.TenantID, ref.QueueID)
+	}
+}
+
+// rollup, kapanmış ve özeti henüz yazılmamış aralıkları Postgres'e yazar. Her aralığı tek bir replika sahiplenir;
+// yazım başarısız olursa sahiplik bırakılır ve sonraki turda yeniden denenir.
+func (e *Engine) rollup(ctx context.Context) {
+	current := IntervalStart(time.Now())
+	for i := 1; i <= rollupLookback; i++ {
+		start := current.Add(-time.Duration(i) * Interval)
+		claimed, err := e.rdb.SetNX(ctx, rollupKey(start), 1, counterTTL).Result()
+		if err != nil || !claimed {
+			continue
+		}
+		if err := e.rollupInterval(ctx, start); err != nil {
+			e.rdb.Del(ctx, rollupKey(start))
+			e.log.Warn().Str("event", "QUEUE_STATS_ROLLUP_FAILED").Time("interval_start", start).Err(err).Msg("Aralık özeti yazılamadı, tekrar denenecek.")
+			return
+		}
+	}
+}
+
+func (e *Engine) rollupInterval(ctx context.Context, start time.Time) error {
+	refs, err := e.Queues(ctx, "")
+	if err != nil {
+		return err
+	}
+	written := 0
+	for _, ref := range refs {
+		c, err := e.Counters(ctx, ref.TenantID, ref.QueueID, start)
+		if err != nil {
+			return err
+		}
+		if c.empty() {
+			continue
+		}
+		err = database.UpsertQueueInterval(ctx, e.db, database.QueueInterval{
+			TenantID:           ref.TenantID,
+			QueueID:            ref.QueueID,
+			IntervalStart:      start,
+			Offered:            c.Offered,
+			Answered:           c.Answered,
+			Abandoned:          c.Abandoned,
+			AnsweredWithinSL:   c.AnsweredWithinSL,
+			AnswerWaitMsTotal:  c.AnswerWaitMsTotal,
+			AbandonWaitMsTotal: c.AbandonWaitMsTotal,
+			TimedOut:           c.TimedOut,
+			Callbacks:          c.Callbacks,
+			Voicemails:         c.Voicemails,
+		})
+		if err != nil {
+			return err
+		}
+		written++
+	}
+	if written > 0 {
+		e.log.Info().Str("event", "QUEUE_STATS_ROLLED_UP").Time("interval_start", start).Int("queues", written).Msg("📊 Kuyruk aralık özetleri yazıldı.")
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Package queuestats, kuyruk ve ajan durum geçişlerinden çağrı merkezi göstergelerini (bekleyen çağrı, en uzun
+// bekleme, ASA, terk oranı, hizmet seviyesi, duruma göre ajanlar) üretir. Sayaçlar replikalar arasında ortak
+// olması için Redis'te 15 dakikalık aralıklarla tutulur; kapanan aralıklar Postgres'e özet olarak yazılır.
+package queuestats
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
+	"github.com/sentiric/sentiric-agent-service/internal/presence"
+)
+
+// Interval, sayaçların ve Postgres özetlerinin aralık uzunluğudur.
+const Interval = 15 * time.Minute
+
+// counterTTL, aralık sayaçlarının Redis'te kalma süresidir; özet yazılamazsa sonraki turlarda yeniden denenebilsin diye uzundur.
+const counterTTL = 48 * time.Hour
+
+// Aralık sayacı alanları.
+const (
+	fieldOffered            = "offered"
+	fieldAnswered           = "answered"
+	fieldAbandoned          = "abandoned"
+	fieldAnsweredWithinSL   = "answered_within_sl"
+	fieldAnswerWaitMsTotal  = "answer_wait_ms"
+	fieldAbandonWaitMsTotal = "abandon_wait_ms"
+	fieldTimedOut           = "timed_out"
+	fieldCallbacks          = "callbacks"
+	fieldVoicemails         = "voicemails"
+)
+
+// Kuyruktan cevaplanmadan ve terk edilmeden çıkış nedenleri (bkz. RecordExit).
+const (
+	// ExitTimedOut, azami bekleme süresi dolduğu için taşma aksiyonuna yönlenen çağrıdır.
+	ExitTimedOut = "timed_out"
+	// ExitCallback, beklerken geri aramaya çevrilen çağrıdır.
+	ExitCallback = "callback"
+	// ExitVoicemail, beklerken sesli mesaja yönlenen çağrıdır.
+	ExitVoicemail = "voicemail"
+)
+
+var exitFields = map[string]string{
+	ExitTimedOut:  fieldTimedOut,
+	ExitCallback:  fieldCallbacks,
+	ExitVoicemail: fieldVoicemails,
+}
+
+func counterKey(tenantID, queueID string, start time.Time) string {
+	return "stats:" + tenantID + ":" + queueID + ":" + strconv.FormatInt(start.Unix(), 10)
+}
+
+// queuesKey, istatistiği tutulan kuyrukları "tenant|queue" üyeleriyle tutar.
+const queuesKey = "stats:queues"
+
+// IntervalStart, verilen anın içinde bulunduğu aralığın başlangıcıdır.
+func IntervalStart(t time.Time) time.Time {
+	return t.Truncate(Interval)
+}
+
+// Counters, bir kuyruğun bir aralıktaki toplamlarıdır.
+type Counters struct {
+	Offered            int64
+	Answered           int64
+	Abandoned          int64
+	AnsweredWithinSL   int64
+	AnswerWaitMsTotal  int64
+	AbandonWaitMsTotal int64
+	TimedOut           int64
+	Callbacks          int64
+	Voicemails         int64
+}
+
+// ASA, ortalama cevaplanma süresidir (average speed of answer).
+func (c Counters) ASA() time.Duration {
+	if c.Answered == 0 {
+		return 0
+	}
+	return time.Duration(c.AnswerWaitMsTotal/c.Answered) * time.Millisecond
+}
+
+// AbandonRate, kuyruğa giren çağrılardan arayanın kapattığı oranıdır (0..1).
+func (c Counters) AbandonRate() float64 {
+	if c.Offered == 0 {
+		return 0
+	}
+	return float64(c.Abandoned) / float64(c.Offered)
+}
+
+// ServiceLevel, eşik içinde cevaplanan çağrıların kuyruktan çıkan (cevaplanan + terk eden) çağrılara oranıdır (0..1).
+// Aralıkta hiç çağrı sonuçlanmadıysa 1 döner.
+func (c Counters) ServiceLevel() float64 {
+	handled := c.Answered + c.Abandoned
+	if handled == 0 {
+		return 1
+	}
+	return float64(c.AnsweredWithinSL) / float64(handled)
+}
+
+func (c Counters) empty() bool {
+	return c == Counters{}
+}
+
+// QueueSnapshot, bir kuyruğun anlık durumu ve içinde bulunulan aralığın göstergeleridir.
+type QueueSnapshot struct {
+	TenantID      string
+	QueueID       string
+	Waiting       int64
+	LongestWait   time.Duration
+	IntervalStart time.Time
+	Counters
+}
+
+// Gauges, göstergelerin yazıldığı Prometheus metrikleridir. Kuyruk metrikleri tenant_id ve queue_id,
+// ajan metriği tenant_id ve status etiketlerini taşır.
+type Gauges struct {
+	Waiting      *prometheus.GaugeVec
+	LongestWait  *prometheus.GaugeVec
+	ASA          *prometheus.GaugeVec
+	AbandonRate  *prometheus.GaugeVec
+	ServiceLevel *prometheus.GaugeVec
+	Agents       *prometheus.GaugeVec
+}
+
+type Engine struct {
+	rdb      *redis.Client
+	db       *sql.DB
+	queue    *callqueue.Queue
+	presence *presence.Store
+	gauges   Gauges
+	refresh  time.Duration
+	log      zerolog.Logger
+}
+
+// NewEngine, göstergeleri refresh aralığıyla yenileyen bir motor oluşturur.
+func NewEngine(rdb *redis.Client, db *sql.DB, q *callqueue.Queue, p *presence.Store, g Gauges, refresh time.Duration, log zerolog.Logger) *Engine {
+	return &Engine{rdb: rdb, db: db, queue: q, presence: p, gauges: g, refresh: refresh, log: log}
+}
+
+// RecordOffered, kuyruğa yeni giren çağrıyı sayar.
+func (e *Engine) RecordOffered(ctx context.Context, tenantID, queueID string) {
+	e.incr(ctx, tenantID, queueID, map[string]int64{fieldOffered: 1})
+}
+
+// RecordAnswered, kuyruktan ajana bağlanan çağrıyı bekleme süresiyle sayar; bekleme slThreshold'u
+// aşmadıysa hizmet seviyesi içinde kabul edilir.
+func (e *Engine) RecordAnswered(ctx context.Context, tenantID, queueID string, waited, slThreshold time.Duration) {
+	fields := map[string]int64{fieldAnswered: 1, fieldAnswerWaitMsTotal: waited.Milliseconds()}
+	if waited <= slThreshold {
+		fields[fieldAnsweredWithinSL] = 1
+	}
+	e.incr(ctx, tenantID, queueID, fields)
+}
+
+// RecordAbandoned, kuyrukta beklerken kapatan arayanı bekleme süresiyle sayar.
+func (e *Engine) RecordAbandoned(ctx context.Context, tenantID, queueID string, waited time.Duration) {
+	e.incr(ctx, tenantID, queueID, map[string]int64{fieldAbandoned: 1, fieldAbandonWaitMsTotal: waited.Milliseconds()})
+}
+
+// RecordExit, kuyruktan cevaplanmadan ve terk edilmeden çıkan çağrıyı çıkış nedeniyle sayar.
+func (e *Engine) RecordExit(ctx context.Context, tenantID, queueID, exit string) {
+	if f, ok := exitFields[exit]; ok {
+		e.incr(ctx, tenantID, queueID, map[string]int64{f: 1})
+	}
+}
+
+func (e *Engine) incr(ctx context.Context, tenantID, queueID string, fields map[string]int64) {
+	key := counterKey(tenantID, queueID, IntervalStart(time.Now()))
+	_, err := e.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for f, v := range fields {
+			pipe.HIncrBy(ctx, key, f, v)
+		}
+		pipe.Expire(ctx, key, counterTTL)
+		pipe.SAdd(ctx, queuesKey, tenantID+"|"+queueID)
+		return nil
+	})
+	if err != nil {
+		e.log.Warn().Str("event", "QUEUE_STATS_RECORD_FAILED").Str("tenant_id", tenantID).Str("queue_id", queueID).Err(err).Msg("Kuyruk istatistiği yazılamadı.")
+	}
+}
+
+// Counters, kuyruğun verilen aralıktaki toplamlarını döner.
+func (e *Engine) Counters(ctx context.Context, tenantID, queueID string, start time.Time) (Counters, error) {
+	vals, err := e.rdb.HGetAll(ctx, counterKey(tenantID, queueID, start)).Result()
+	if err != nil {
+		return Counters{}, err
+	}
+	n := func(f string) int64 {
+		v, _ := strconv.ParseInt(vals[f], 10, 64)
+		return v
+	}
+	return Counters{
+		Offered:            n(fieldOffered),
+		Answered:           n(fieldAnswered),
+		Abandoned:          n(fieldAbandoned),
+		AnsweredWithinSL:   n(fieldAnsweredWithinSL),
+		AnswerWaitMsTotal:  n(fieldAnswerWaitMsTotal),
+		AbandonWaitMsTotal: n(fieldAbandonWaitMsTotal),
+		TimedOut:           n(fieldTimedOut),
+		Callbacks:          n(fieldCallbacks),
+		Voicemails:         n(fieldVoicemails),
+	}, nil
+}
+
+// Queues, istatistiği tutulan kuyrukları döner; tenantID boş değilse yalnızca o tenant'ınkiler.
+func (e *Engine) Queues(ctx context.Context, tenantID string) ([]callqueue.Ref, error) {
+	members, err := e.rdb.SMembers(ctx, queuesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]callqueue.Ref, 0, len(members))
+	for _, m := range members {
+		tenant, queueID, ok := strings.Cut(m, "|")
+		if !ok || (tenantID != "" && tenant != tenantID) {
+			continue
+		}
+		refs = append(refs, callqueue.Ref{TenantID: tenant, QueueID: queueID})
+	}
+	return refs, nil
+}
+
+// pruneQueueScript, son aralıklarda hiç sayacı kalmamış kuyruğu istatistik listesinden çıkarır. Bu arada yazılan
+// bir sayaç kuyruğu listeye yeniden ekler.
+var pruneQueueScript = redis.NewScript(`
+if redis.call("EXISTS", unpack(KEYS, 2)) > 0 then
+	return 0
+end
+return redis.call("SREM", KEYS[1], ARGV[1])`)
+
+// prune, boş olan ve içinde bulunulan aralık ile özeti aranan aralıklarda sayacı olmayan kuyruğu listeden çıkarır.
+func (e *Engine) prune(ctx context.Context, ref callqueue.Ref, now time.Time) (bool, error) {
+	current := IntervalStart(now)
+	keys := []string{queuesKey}
+	for i := 0; i <= rollupLookback; i++ {
+		keys = append(keys, counterKey(ref.TenantID, ref.QueueID, current.Add(-time.Duration(i)*Interval)))
+	}
+	n, err := pruneQueueScript.Run(ctx, e.rdb, keys, ref.TenantID+"|"+ref.QueueID).Int()
+	return n == 1, err
+}
+
+// Snapshot, kuyruğun anlık bekleyen sayısını, en uzun beklemesini ve içinde bulunulan aralığın sayaçlarını döner.
+func (e *Engine) Snapshot(ctx context.Context, tenantID, queueID string) (*QueueSnapshot, error) {
+	now := time.Now()
+	waiting, oldest, err := e.queue.Backlog(ctx, tenantID, queueID)
+	if err != nil {
+		return nil, err
+	}
+	start := IntervalStart(now)
+	c, err := e.Counters(ctx, tenantID, queueID, start)
+	if err != nil {
+		return nil, err
+	}
+	snap := &QueueSnapshot{TenantID: tenantID, QueueID: queueID, Waiting: waiting, IntervalStart: start, Counters: c}
+	if !oldest.IsZero() {
+		snap.LongestWait = now.Sub(oldest)
+	}
+	return snap, nil
+}
+
+// Agents, tenant'ın bağlı ajanlarını durumlarına göre sayar.
+func (e *Engine) Agents(ctx context.Context, tenantID string) (map[string]int, error) {
+	return e.presence.CountByStatus(ctx, tenantID)
+}
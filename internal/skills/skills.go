@@ -0,0 +1,104 @@
+// Package skills, dialplan'ın istediği yetenekleri (required_skills) ayrıştırır ve boştaki ajanları
+// yetkinlik seviyelerine göre puanlar. Bekleme uzadıkça istenen seviyeler gevşetilir (relaxation).
+package skills
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLevel, seviyesi belirtilmeyen yetenek için istenen en düşük yetkinliktir ("tr" == "tr:1").
+const DefaultLevel = 1
+
+// Requirement, bir üyenin ajanda aradığı yetenek ve en düşük yetkinlik seviyesidir.
+// Level 0 ise yetenek zorunlu değildir; yalnızca puanlamada tercih sebebidir.
+type Requirement struct {
+	Skill string
+	Level int
+}
+
+// ParseRequirements, "billing:3,tr" biçimindeki listeyi ayrıştırır. Yetenek adları küçük harfe çevrilir;
+// seviyesi okunamayan yetenek DefaultLevel ile istenir.
+func ParseRequirements(s string) []Requirement {
+	var reqs []Requirement
+	for _, part := range strings.Split(s, ",") {
+		name, levelStr, hasLevel := strings.Cut(strings.TrimSpace(part), ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		level := DefaultLevel
+		if hasLevel {
+			if v, err := strconv.Atoi(strings.TrimSpace(levelStr)); err == nil && v >= 0 {
+				level = v
+			}
+		}
+		reqs = append(reqs, Requirement{Skill: name, Level: level})
+	}
+	return reqs
+}
+
+// WithLanguage, çağrının dilini (ör. "tr-TR" → "tr") DefaultLevel ile şartlara ekler. Dil zaten istenmişse
+// dialplan'ın verdiği seviye korunur.
+func WithLanguage(reqs []Requirement, languageCode string) []Requirement {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(languageCode)), "-")
+	if lang == "" {
+		return reqs
+	}
+	for _, r := range reqs {
+		if r.Skill == lang {
+			return reqs
+		}
+	}
+	return append(reqs, Requirement{Skill: lang, Level: DefaultLevel})
+}
+
+// RelaxSteps, üyenin beklediği süreye göre kaç seviye gevşetme uygulanacağını döner: her relaxAfter
+// süresi istenen seviyeleri bir düşürür. relaxAfter sıfır veya negatifse gevşetme yapılmaz.
+func RelaxSteps(waited, relaxAfter time.Duration) int {
+	if relaxAfter <= 0 || waited <= 0 {
+		return 0
+	}
+	return int(waited / relaxAfter)
+}
+
+// Relax, istenen seviyeleri steps kadar düşürür. Seviyesi sıfıra inen yetenek zorunlu olmaktan çıkar.
+func Relax(reqs []Requirement, steps int) []Requirement {
+	if steps <= 0 {
+		return reqs
+	}
+	out := make([]Requirement, len(reqs))
+	for i, r := range reqs {
+		r.Level = max(r.Level-steps, 0)
+		out[i] = r
+	}
+	return out
+}
+
+// Score, ajanın şartları karşılayıp karşılamadığını ve puanını döner. Puan, istenen yeteneklerdeki
+// yetkinliklerin toplamıdır; zorunlu olmaktan çıkmış yetenekler de puana katkı verir.
+func Score(agentSkills map[string]int, reqs []Requirement) (int, bool) {
+	score := 0
+	for _, r := range reqs {
+		p := agentSkills[r.Skill]
+		if p < r.Level {
+			return 0, false
+		}
+		score += p
+	}
+	return score, true
+}
+
+// Best, adaylar arasından şartları karşılayan en yüksek puanlı ajanı seçer. Adaylar en uzun süredir boşta
+// olandan başlayarak sıralı gelmelidir; eşit puanda önce gelen (daha uzun bekleyen) ajan seçilir.
+func Best(candidates []string, agentSkills map[string]map[string]int, reqs []Requirement) (string, bool) {
+	best, bestScore := "", -1
+	for _, agentID := range candidates {
+		score, ok := Score(agentSkills[agentID], reqs)
+		if ok && score > bestScore {
+			best, bestScore = agentID, score
+		}
+	}
+	return best, bestScore >= 0
+}
@@ -0,0 +1,113 @@
+package skills
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRequirements(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Requirement
+	}{
+		{"empty", "", nil},
+		{"level and default level", "billing:3,tr", []Requirement{{"billing", 3}, {"tr", DefaultLevel}}},
+		{"spaces and case are normalised", " Billing : 2 , EN ", []Requirement{{"billing", 2}, {"en", DefaultLevel}}},
+		{"unreadable level falls back to default", "billing:x", []Requirement{{"billing", DefaultLevel}}},
+		{"negative level falls back to default", "billing:-1", []Requirement{{"billing", DefaultLevel}}},
+		{"zero level is a preference", "vip:0", []Requirement{{"vip", 0}}},
+		{"empty parts are skipped", ",,tr,:3", []Requirement{{"tr", DefaultLevel}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRequirements(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequirements(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		reqs []Requirement
+		lang string
+		want []Requirement
+	}{
+		{"adds the call language", []Requirement{{"billing", 3}}, "tr", []Requirement{{"billing", 3}, {"tr", DefaultLevel}}},
+		{"uses the primary subtag", nil, "tr-TR", []Requirement{{"tr", DefaultLevel}}},
+		{"keeps the dialplan level", []Requirement{{"en", 4}}, "en-US", []Requirement{{"en", 4}}},
+		{"no language leaves requirements unchanged", []Requirement{{"billing", 3}}, "", []Requirement{{"billing", 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithLanguage(tt.reqs, tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithLanguage(%v, %q) = %v, want %v", tt.reqs, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelax(t *testing.T) {
+	reqs := []Requirement{{"billing", 3}, {"tr", 1}}
+	tests := []struct {
+		name       string
+		waited     time.Duration
+		relaxAfter time.Duration
+		want       []Requirement
+	}{
+		{"relaxation disabled", time.Hour, 0, reqs},
+		{"before the first step", 59 * time.Second, time.Minute, reqs},
+		{"one step", 90 * time.Second, time.Minute, []Requirement{{"billing", 2}, {"tr", 0}}},
+		{"levels do not go below zero", 10 * time.Minute, time.Minute, []Requirement{{"billing", 0}, {"tr", 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Relax(reqs, RelaxSteps(tt.waited, tt.relaxAfter))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Relax after %v = %v, want %v", tt.waited, got, tt.want)
+			}
+		})
+	}
+	if reqs[0].Level != 3 {
+		t.Errorf("Relax modified its input: %v", reqs)
+	}
+}
+
+func TestBest(t *testing.T) {
+	agentSkills := map[string]map[string]int{
+		"a1": {"billing": 2, "tr": 5},
+		"a2": {"billing": 4, "tr": 3},
+		"a3": {"billing": 4, "tr": 3},
+		"a4": {"en": 5},
+	}
+	candidates := []string{"a1", "a2", "a3", "a4"}
+
+	tests := []struct {
+		name   string
+		reqs   []Requirement
+		want   string
+		wantOK bool
+	}{
+		{"no requirements picks the longest idle", nil, "a1", true},
+		{"highest proficiency wins", []Requirement{{"billing", 1}}, "a2", true},
+		{"tie goes to the longer idle agent", []Requirement{{"billing", 3}, {"tr", 1}}, "a2", true},
+		{"minimum level filters candidates", []Requirement{{"tr", 4}}, "a1", true},
+		{"relaxed skill still scores", []Requirement{{"billing", 0}}, "a2", true},
+		{"no agent qualifies", []Requirement{{"de", 1}}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Best(candidates, agentSkills, tt.reqs)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Best(%v) = %q, %v, want %q, %v", tt.reqs, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DedupStore, işlenmiş olayların idempotency anahtarlarını belirli bir pencere
+// süresince Redis'te hatırlar. Böylece RabbitMQ yeniden teslimatları tekrar işlenmez.
+type DedupStore struct {
+	rdb    *redis.Client
+	window time.Duration
+}
+
+// DefaultDedupWindow, geçerli bir pencere verilmediğinde kullanılan süredir.
+const DefaultDedupWindow = 10 * time.Minute
+
+// NewDedupStore, anahtarları window süresince tutan bir depo oluşturur. Pencere pozitif değilse
+// DefaultDedupWindow kullanılır; TTL'siz anahtarlar hiç silinmezdi.
+func NewDedupStore(rdb *redis.Client, window time.Duration) *DedupStore {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return &DedupStore{rdb: rdb, window: window}
+}
+
+func dedupKey(key string) string { return "dedup:event:" + key }
+
+// Seen, anahtarın pencere içinde işlenmiş olarak işaretlenip işaretlenmediğini döner.
+func (d *DedupStore) Seen(ctx context.Context, key string) (bool, error) {
+	n, err := d.rdb.Exists(ctx, dedupKey(key)).Result()
+	return n > 0, err
+}
+
+// MarkProcessed, olay işleyicisi tamamlandıktan sonra anahtarı pencere süresince işlenmiş olarak işaretler.
+// İşleyici panik yapar veya süreç yarıda kapanırsa anahtar yazılmaz; yeniden teslimat ve DLQ tekrarı işlenir.
+func (d *DedupStore) MarkProcessed(ctx context.Context, key string) error {
+	return d.rdb.Set(ctx, dedupKey(key), "1", d.window).Err()
+}
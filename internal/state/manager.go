@@ -4,14 +4,92 @@ package state
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/sentiric/sentiric-agent-service/internal/callqueue"
 	"github.com/sentiric/sentiric-agent-service/internal/constants"
 )
 
-const SessionTTL = 2 * time.Hour
+const (
+	SessionTTL  = 2 * time.Hour
+	AffinityTTL = 30 * 24 * time.Hour
+)
+
+// IdentifiedUser, çağrı için tanınan kullanıcının orkestrasyonda ihtiyaç duyulan özetidir.
+type IdentifiedUser struct {
+	ID                string `json:"id"`
+	Name              string `json:"name,omitempty"`
+	PreferredLanguage string `json:"preferredLanguage,omitempty"`
+	VIPTier           string `json:"vipTier,omitempty"`
+}
+
+// VoiceModelChoice, çağrı için çözümlenen STT/TTS modelleri, seçim kaynakları ve
+// arıza durumunda sırayla denenecek yedeklerdir.
+type VoiceModelChoice struct {
+	SttModelID   string   `json:"sttModelId"`
+	SttSource    string   `json:"sttSource"`
+	TtsModelID   string   `json:"ttsModelId"`
+	TtsSource    string   `json:"ttsSource"`
+	SttFallbacks []string `json:"sttFallbacks,omitempty"`
+	TtsFallbacks []string `json:"ttsFallbacks,omitempty"`
+}
+
+// BridgeLeg, B2BUA üzerinden hedefe açılan ikinci bacağın (B-leg) durumudur.
+// ActionData, köprü kurulamazsa uygulanacak fallback'ler için dialplan verisini saklar.
+type BridgeLeg struct {
+	PeerCallID string            `json:"peerCallId"`
+	TargetURI  string            `json:"targetUri"`
+	StartedAt  time.Time         `json:"startedAt"`
+	AnsweredAt time.Time         `json:"answeredAt,omitempty"`
+	Outcome    string            `json:"outcome,omitempty"`
+	ActionData map[string]string `json:"actionData,omitempty"`
+}
+
+// TransferState, çağrının bir ajana/hedefe devrinin durumudur. Attended (danışmalı) devirde
+// ConsultCallID, hedefle açılan danışma bacağıdır; FromQueue, hedef reddederse çağrının kuyruğa döneceğini belirtir.
+type TransferState struct {
+	Mode          string            `json:"mode"`
+	Status        string            `json:"status"`
+	TargetURI     string            `json:"targetUri"`
+	AgentID       string            `json:"agentId,omitempty"`
+	ConsultCallID string            `json:"consultCallId,omitempty"`
+	FromQueue     bool              `json:"fromQueue,omitempty"`
+	StartedAt     time.Time         `json:"startedAt"`
+	ActionData    map[string]string `json:"actionData,omitempty"`
+	// PriorState, devir başlamadan önceki diyalog durumudur; devir iptal edilir veya reddedilirse geri yüklenir.
+	PriorState constants.DialogState `json:"priorState,omitempty"`
+}
+
+// OutOfHoursDecision, çağrının mesai dışına denk geldiğini ve uygulanan aksiyonu kaydeder.
+type OutOfHoursDecision struct {
+	ScheduleID  string    `json:"scheduleId"`
+	Reason      string    `json:"reason"`
+	HolidayName string    `json:"holidayName,omitempty"`
+	Action      string    `json:"action"`
+	CheckedAt   time.Time `json:"checkedAt"`
+}
+
+// QueueMembership, çağrının girdiği kuyruk ve (talep edildiyse) geri arama kaydıdır.
+type QueueMembership struct {
+	QueueID    string    `json:"queueId"`
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	CallbackID string    `json:"callbackId,omitempty"`
+	// Exit, çağrının kuyruktan nasıl çıktığıdır (cevaplandı, zaman aşımı, geri arama, sesli mesaj); boşsa çağrı
+	// hâlâ kuyrukta, teklif bekliyor veya ajanında çalıyordur.
+	Exit string `json:"exit,omitempty"`
+}
+
+// OutboundDial, agent'ın başlattığı giden çağrının (manuel arama veya geri arama) bağlanacağı ajandır.
+type OutboundDial struct {
+	AgentID  string `json:"agentId"`
+	Answered bool   `json:"answered,omitempty"`
+	// Callback, giden çağrı bir geri aramaysa kuyruk üyesidir; cevapsız kalırsa aynı skorla kuyruğa geri döner.
+	Callback      *callqueue.Entry `json:"callback,omitempty"`
+	CallbackScore float64          `json:"callbackScore,omitempty"`
+}
 
 // CallState, platform genelindeki asenkron orkestrasyonun "Tek Doğruluk Kaynağı"dır.
 type CallState struct {
@@ -25,9 +103,32 @@ type CallState struct {
 	ServerRtpPort  uint32                `json:"serverRtpPort"`
 	CallerRtpAddr  string                `json:"callerRtpAddr"`
 	PipelineActive bool                  `json:"pipelineActive"`
-	CreatedAt      time.Time             `json:"createdAt"`
+	StateChangedAt time.Time             `json:"stateChangedAt,omitempty"`
+	User           *IdentifiedUser       `json:"user,omitempty"`
+	VoiceModels    *VoiceModelChoice     `json:"voiceModels,omitempty"`
+	Bridge         *BridgeLeg            `json:"bridge,omitempty"`
+	Transfer       *TransferState        `json:"transfer,omitempty"`
+	OutOfHours     *OutOfHoursDecision   `json:"outOfHours,omitempty"`
+	Queue          *QueueMembership      `json:"queue,omitempty"`
+	Outbound       *OutboundDial         `json:"outbound,omitempty"`
+	// CallerFailures, arayanın art arda duyulamadığı/anlaşılamadığı tur sayısıdır.
+	CallerFailures int       `json:"callerFailures,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
 }
 
+// WelcomeTemplateID, arayan tanınmışsa kişiselleştirilmiş, değilse misafir karşılama şablonunu seçer.
+func (s *CallState) WelcomeTemplateID() constants.TemplateID {
+	if s.User != nil && s.User.ID != "" {
+		return constants.PromptWelcomeKnownUser
+	}
+	return constants.PromptWelcomeGuest
+}
+
+// ErrStateNotFound, güncellenmek istenen çağrı durumu Redis'te bulunmadığında döner.
+var ErrStateNotFound = errors.New("call state not found")
+
+const maxUpdateRetries = 5
+
 type Manager struct {
 	rdb *redis.Client
 }
@@ -60,6 +161,142 @@ func (m *Manager) Set(ctx context.Context, state *CallState) error {
 	return m.rdb.Set(ctx, "callstate:"+state.CallID, val, SessionTTL).Err()
 }
 
+// Update, çağrı durumunu iyimser kilit (WATCH/MULTI) altında oku-değiştir-yaz ile günceller.
+// Aynı çağrıya eşzamanlı yazan goroutine'lerin birbirinin değişikliğini ezmesini önler.
+func (m *Manager) Update(ctx context.Context, callID string, fn func(*CallState) error) (*CallState, error) {
+	key := "callstate:" + callID
+	var updated *CallState
+
+	txf := func(tx *redis.Tx) error {
+		val, err := tx.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return ErrStateNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("redis get error: %w", err)
+		}
+		var s CallState
+		if err := json.Unmarshal([]byte(val), &s); err != nil {
+			return fmt.Errorf("json unmarshal error: %w", err)
+		}
+		if err := fn(&s); err != nil {
+			return err
+		}
+		out, _ := json.Marshal(&s)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, out, SessionTTL)
+			return nil
+		})
+		if err == nil {
+			updated = &s
+		}
+		return err
+	}
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		err := m.rdb.Watch(ctx, txf, key)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		return updated, err
+	}
+	return nil, fmt.Errorf("call state update conflict: %s", callID)
+}
+
 func (m *Manager) Delete(ctx context.Context, callID string) error {
 	return m.rdb.Del(ctx, "callstate:"+callID).Err()
 }
+
+// SetAgentAffinity, kullanıcının en son görüştüğü ajanı "Direct Match" için hatırlar.
+func (m *Manager) SetAgentAffinity(ctx context.Context, userID, agentID string) error {
+	return m.rdb.Set(ctx, "affinity:user:"+userID, agentID, AffinityTTL).Err()
+}
+
+// GetAgentAffinity, kullanıcının tercihli ajanını döner; kayıt yoksa boş string döner.
+func (m *Manager) GetAgentAffinity(ctx context.Context, userID string) (string, error) {
+	agentID, err := m.rdb.Get(ctx, "affinity:user:"+userID).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return agentID, err
+}
+
+// Agent'ın kendi açtığı bacak türleri; bu bacakların call.started olayı dialplan'a girmez.
+const (
+	LegBridgePeer = "bridge_peer"
+	LegConsult    = "consult"
+	LegOutbound   = "outbound"
+)
+
+// ClassifyLeg, call.started olayındaki çağrının agent'ın açtığı bir bacak olup olmadığını tek Redis
+// round-trip'iyle belirler. Dialplan'dan gelen yeni bir çağrı için boş string döner. Giden aramaların
+// durumu aramadan önce yazıldığından, durumu zaten olan bir çağrı LegOutbound adayıdır.
+func (m *Manager) ClassifyLeg(ctx context.Context, callID string) (string, error) {
+	var bridge, consult, callState *redis.IntCmd
+	_, err := m.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		bridge = pipe.Exists(ctx, "bridge:peer:"+callID)
+		consult = pipe.Exists(ctx, "transfer:consult:"+callID)
+		callState = pipe.Exists(ctx, "callstate:"+callID)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case bridge.Val() > 0:
+		return LegBridgePeer, nil
+	case consult.Val() > 0:
+		return LegConsult, nil
+	case callState.Val() > 0:
+		return LegOutbound, nil
+	}
+	return "", nil
+}
+
+// SetBridgePeer, B-leg çağrı kimliğinden köprünün ait olduğu A-leg'e eşleme kaydeder.
+// B-leg olayları (call.started / call.ended) başka bir replikaya düşse de köprüye bağlanabilir.
+func (m *Manager) SetBridgePeer(ctx context.Context, peerCallID, callID string) error {
+	return m.rdb.Set(ctx, "bridge:peer:"+peerCallID, callID, SessionTTL).Err()
+}
+
+// GetBridgePeer, B-leg'in bağlı olduğu A-leg çağrı kimliğini döner; kayıt yoksa boş string döner.
+func (m *Manager) GetBridgePeer(ctx context.Context, peerCallID string) (string, error) {
+	callID, err := m.rdb.Get(ctx, "bridge:peer:"+peerCallID).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return callID, err
+}
+
+func (m *Manager) DeleteBridgePeer(ctx context.Context, peerCallID string) error {
+	return m.rdb.Del(ctx, "bridge:peer:"+peerCallID).Err()
+}
+
+// SetConsultLeg, attended devirde açılan danışma bacağını devredilen çağrıya eşler.
+func (m *Manager) SetConsultLeg(ctx context.Context, consultCallID, callID string) error {
+	return m.rdb.Set(ctx, "transfer:consult:"+consultCallID, callID, SessionTTL).Err()
+}
+
+// GetConsultLeg, danışma bacağının ait olduğu çağrıyı döner; kayıt yoksa boş string döner.
+func (m *Manager) GetConsultLeg(ctx context.Context, consultCallID string) (string, error) {
+	callID, err := m.rdb.Get(ctx, "transfer:consult:"+consultCallID).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return callID, err
+}
+
+func (m *Manager) DeleteConsultLeg(ctx context.Context, consultCallID string) error {
+	return m.rdb.Del(ctx, "transfer:consult:"+consultCallID).Err()
+}
+
+// MarkModelUnavailable, bir STT/TTS modelini verilen süre boyunca seçim dışı bırakır.
+func (m *Manager) MarkModelUnavailable(ctx context.Context, modelID string, ttl time.Duration) error {
+	return m.rdb.Set(ctx, "model:unavailable:"+modelID, "1", ttl).Err()
+}
+
+// IsModelUnavailable, modelin yakın zamanda arızalı olarak işaretlenip işaretlenmediğini döner.
+func (m *Manager) IsModelUnavailable(ctx context.Context, modelID string) (bool, error) {
+	n, err := m.rdb.Exists(ctx, "model:unavailable:"+modelID).Result()
+	return n > 0, err
+}
@@ -0,0 +1,139 @@
+// Package voicemodel, bir çağrı için kullanılacak STT/TTS modellerini
+// dialplan, tenant ayarları ve model erişilebilirliğine göre çözer.
+package voicemodel
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sentiric/sentiric-agent-service/internal/database"
+	"github.com/sentiric/sentiric-agent-service/internal/state"
+)
+
+const (
+	DefaultSttModelID = "whisper:default"
+	DefaultTtsModelID = "coqui:default"
+)
+
+// Seçimin nereden geldiğini belirten kaynaklar.
+const (
+	SourceDialplan = "dialplan"
+	SourceTenant   = "tenant"
+	SourceSystem   = "system"
+	SourceDefault  = "default"
+)
+
+// AvailabilityChecker, bir modelin geçici olarak devre dışı olup olmadığını bildirir.
+type AvailabilityChecker interface {
+	IsModelUnavailable(ctx context.Context, modelID string) (bool, error)
+}
+
+type Selector struct {
+	db           *sql.DB
+	availability AvailabilityChecker
+	log          zerolog.Logger
+}
+
+func NewSelector(db *sql.DB, availability AvailabilityChecker, log zerolog.Logger) *Selector {
+	return &Selector{db: db, availability: availability, log: log}
+}
+
+// candidate, aday model kimliği ve kaynağıdır.
+type candidate struct {
+	modelID string
+	source  string
+}
+
+// Resolve, dialplan override'ı > tenant ayarı > sistem ayarı > sabit varsayılan sırasıyla
+// ilk erişilebilir modeli seçer. Veritabanı ulaşılamazsa override ve varsayılanlarla devam eder.
+func (s *Selector) Resolve(ctx context.Context, tenantID, languageCode string, actionData map[string]string) *state.VoiceModelChoice {
+	var sttCandidates, ttsCandidates []candidate
+
+	if v := actionData["stt_model_id"]; v != "" {
+		sttCandidates = append(sttCandidates, candidate{v, SourceDialplan})
+	}
+	if v := actionData["tts_model_id"]; v != "" {
+		ttsCandidates = append(ttsCandidates, candidate{v, SourceDialplan})
+	}
+	// Geriye dönük uyumluluk: voice_id, TTS override'ı olarak kabul edilir.
+	if v := actionData["voice_id"]; v != "" {
+		ttsCandidates = append(ttsCandidates, candidate{v, SourceDialplan})
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	settings, err := database.GetVoiceModelSettings(dbCtx, s.db, tenantID, languageCode)
+	cancel()
+	if err != nil {
+		s.log.Warn().Str("event", "VOICE_MODEL_SETTINGS_UNAVAILABLE").Str("tenant_id", tenantID).Err(err).Msg("Tenant model ayarları okunamadı, varsayılanlar kullanılacak.")
+	}
+
+	// Önce tüm birincil modeller, ardından tanımlı yedekler aday listesine girer.
+	for _, v := range settings {
+		sttCandidates = appendIf(sttCandidates, v.SttModelID, sourceOf(v, tenantID))
+		ttsCandidates = appendIf(ttsCandidates, v.TtsModelID, sourceOf(v, tenantID))
+	}
+	for _, v := range settings {
+		sttCandidates = appendIf(sttCandidates, v.FallbackSttModelID, sourceOf(v, tenantID))
+		ttsCandidates = appendIf(ttsCandidates, v.FallbackTtsModelID, sourceOf(v, tenantID))
+	}
+
+	sttCandidates = appendIf(sttCandidates, DefaultSttModelID, SourceDefault)
+	ttsCandidates = appendIf(ttsCandidates, DefaultTtsModelID, SourceDefault)
+
+	stt, sttRest := s.pick(ctx, sttCandidates)
+	tts, ttsRest := s.pick(ctx, ttsCandidates)
+
+	return &state.VoiceModelChoice{
+		SttModelID:   stt.modelID,
+		SttSource:    stt.source,
+		TtsModelID:   tts.modelID,
+		TtsSource:    tts.source,
+		SttFallbacks: sttRest,
+		TtsFallbacks: ttsRest,
+	}
+}
+
+// pick, erişilebilir ilk adayı ve ondan sonra gelen adayları (yedek listesi) döner.
+// Hiçbir aday erişilebilir değilse son aday (sabit varsayılan) kullanılır.
+func (s *Selector) pick(ctx context.Context, candidates []candidate) (candidate, []string) {
+	for i, c := range candidates {
+		unavailable, err := s.availability.IsModelUnavailable(ctx, c.modelID)
+		if err == nil && unavailable {
+			s.log.Info().Str("event", "VOICE_MODEL_SKIPPED").Str("model_id", c.modelID).Msg("Model geçici olarak erişilemez, yedeğe geçiliyor.")
+			continue
+		}
+		return c, modelIDs(candidates[i+1:])
+	}
+	last := candidates[len(candidates)-1]
+	return last, nil
+}
+
+func sourceOf(v database.VoiceModelSettings, tenantID string) string {
+	if v.TenantID == "system" && tenantID != "system" {
+		return SourceSystem
+	}
+	return SourceTenant
+}
+
+func appendIf(candidates []candidate, modelID, source string) []candidate {
+	if modelID == "" {
+		return candidates
+	}
+	for _, c := range candidates {
+		if c.modelID == modelID {
+			return candidates
+		}
+	}
+	return append(candidates, candidate{modelID, source})
+}
+
+func modelIDs(candidates []candidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.modelID)
+	}
+	return ids
+}
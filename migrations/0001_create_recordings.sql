@@ -0,0 +1,25 @@
+-- Agent Service: çağrı ses kayıtları.
+-- call.recording.available olaylarıyla doldurulur ve GetConversationTranscript
+-- üzerinden transcript ile birlikte döndürülür. conversations tablosu başka bir servise aittir; yabancı anahtar
+-- yalnızca tablo varsa eklenir, böylece migration o servisin şemasını beklerken takılmaz.
+CREATE TABLE IF NOT EXISTS recordings (
+    id              BIGSERIAL PRIMARY KEY,
+    conversation_id UUID        NOT NULL,
+    call_id         TEXT        NOT NULL,
+    bucket          TEXT        NOT NULL,
+    recording_uri   TEXT        NOT NULL,
+    public_url      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (conversation_id, recording_uri)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recordings_call_id ON recordings (call_id);
+
+DO $$
+BEGIN
+    IF to_regclass('conversations') IS NOT NULL
+        AND NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'recordings_conversation_id_fkey') THEN
+        ALTER TABLE recordings ADD CONSTRAINT recordings_conversation_id_fkey
+            FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE;
+    END IF;
+END $$;
@@ -0,0 +1,13 @@
+-- Agent Service: tenant ve dil bazlı STT/TTS model seçimi.
+-- language_code = '*' satırı tenant'ın tüm diller için varsayılanıdır.
+-- tenant_id = 'system' satırları platform genelindeki varsayılanlardır.
+CREATE TABLE IF NOT EXISTS tenant_voice_models (
+    tenant_id             TEXT        NOT NULL,
+    language_code         TEXT        NOT NULL DEFAULT '*',
+    stt_model_id          TEXT,
+    tts_model_id          TEXT,
+    fallback_stt_model_id TEXT,
+    fallback_tts_model_id TEXT,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, language_code)
+);
@@ -0,0 +1,25 @@
+-- Agent Service: anons ve şablon kataloğu değişikliklerini LISTEN/NOTIFY ile yayınlar.
+-- Agent replikaları 'catalog_changed' kanalını dinler; payload değişen tablonun adıdır. announcements ve templates
+-- başka bir servise aittir; tetikleyiciler yalnızca tablo varsa kurulur, yoksa önbellek TTL ile yenilenir.
+CREATE OR REPLACE FUNCTION notify_catalog_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('catalog_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+BEGIN
+    IF to_regclass('announcements') IS NOT NULL THEN
+        DROP TRIGGER IF EXISTS announcements_catalog_changed ON announcements;
+        CREATE TRIGGER announcements_catalog_changed
+            AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON announcements
+            FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();
+    END IF;
+    IF to_regclass('templates') IS NOT NULL THEN
+        DROP TRIGGER IF EXISTS templates_catalog_changed ON templates;
+        CREATE TRIGGER templates_catalog_changed
+            AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON templates
+            FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();
+    END IF;
+END $$;
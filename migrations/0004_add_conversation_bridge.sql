@@ -0,0 +1,13 @@
+-- Agent Service: köprülenen (BRIDGE_CALL) çağrıların hedefi, sonucu ve süresi.
+-- conversations başka bir servise aittir; kolonlar yalnızca tablo varsa eklenir.
+DO $$
+BEGIN
+    IF to_regclass('conversations') IS NOT NULL THEN
+        ALTER TABLE conversations
+            ADD COLUMN IF NOT EXISTS bridge_target           TEXT,
+            ADD COLUMN IF NOT EXISTS bridge_outcome          TEXT,
+            ADD COLUMN IF NOT EXISTS bridge_answered_at      TIMESTAMPTZ,
+            ADD COLUMN IF NOT EXISTS bridge_ended_at         TIMESTAMPTZ,
+            ADD COLUMN IF NOT EXISTS bridge_duration_seconds INTEGER;
+    END IF;
+END $$;
@@ -0,0 +1,20 @@
+-- Agent Service: ECHO_TEST çağrılarının medya yolu ölçümleri (destek ekibinin trunk doğrulaması için).
+CREATE TABLE IF NOT EXISTS echo_test_diagnostics (
+    id              BIGSERIAL   PRIMARY KEY,
+    call_id         TEXT        NOT NULL,
+    tenant_id       TEXT        NOT NULL,
+    caller_rtp_addr TEXT,
+    server_rtp_port INTEGER,
+    samples         INTEGER     NOT NULL DEFAULT 0,
+    latency_ms      DOUBLE PRECISION,
+    jitter_ms       DOUBLE PRECISION,
+    min_rtt_ms      DOUBLE PRECISION,
+    max_rtt_ms      DOUBLE PRECISION,
+    success         BOOLEAN     NOT NULL,
+    failure_reason  TEXT,
+    started_at      TIMESTAMPTZ NOT NULL,
+    finished_at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_echo_test_diagnostics_tenant ON echo_test_diagnostics (tenant_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_echo_test_diagnostics_call ON echo_test_diagnostics (call_id);
@@ -0,0 +1,36 @@
+-- Agent Service: tenant bazlı çalışma saatleri ve tatil takvimi.
+-- Kuyruğa alma (ENQUEUE_CALL) öncesinde kontrol edilir; mesai dışında out_of_hours_action uygulanır.
+CREATE TABLE IF NOT EXISTS business_schedules (
+    tenant_id                    TEXT        NOT NULL,
+    id                           TEXT        NOT NULL,
+    timezone                     TEXT        NOT NULL DEFAULT 'Europe/Istanbul',
+    -- ai | announcement | voicemail | callback | hangup
+    out_of_hours_action          TEXT        NOT NULL DEFAULT 'announcement',
+    out_of_hours_announcement_id TEXT,
+    updated_at                   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, id)
+);
+
+-- day_of_week: 0 = Pazar ... 6 = Cumartesi. close_time < open_time gece yarısını aşan vardiyadır.
+CREATE TABLE IF NOT EXISTS business_hours (
+    tenant_id   TEXT     NOT NULL,
+    schedule_id TEXT     NOT NULL,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    open_time   TIME     NOT NULL,
+    close_time  TIME     NOT NULL,
+    FOREIGN KEY (tenant_id, schedule_id) REFERENCES business_schedules (tenant_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_hours_schedule ON business_hours (tenant_id, schedule_id, day_of_week);
+
+-- open_time/close_time boşsa gün tamamen kapalıdır; doluysa o gün için kısaltılmış mesai uygulanır.
+CREATE TABLE IF NOT EXISTS holidays (
+    tenant_id    TEXT NOT NULL,
+    schedule_id  TEXT NOT NULL,
+    holiday_date DATE NOT NULL,
+    name         TEXT,
+    open_time    TIME,
+    close_time   TIME,
+    PRIMARY KEY (tenant_id, schedule_id, holiday_date),
+    FOREIGN KEY (tenant_id, schedule_id) REFERENCES business_schedules (tenant_id, id) ON DELETE CASCADE
+);
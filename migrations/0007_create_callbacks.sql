@@ -0,0 +1,18 @@
+-- Agent Service: kuyruktaki arayanların geri arama talepleri.
+-- Sıra Redis kuyruğunda korunur; bu tablo talebin yaşam döngüsünü raporlama için saklar.
+CREATE TABLE IF NOT EXISTS callbacks (
+    id               TEXT        PRIMARY KEY,
+    tenant_id        TEXT        NOT NULL,
+    queue_id         TEXT        NOT NULL,
+    original_call_id TEXT,
+    callback_uri     TEXT        NOT NULL,
+    -- PENDING | DIALING | CONNECTED | NO_ANSWER | FAILED
+    status           TEXT        NOT NULL DEFAULT 'PENDING',
+    attempts         INTEGER     NOT NULL DEFAULT 0,
+    agent_id         TEXT,
+    outbound_call_id TEXT,
+    requested_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_callbacks_tenant_status ON callbacks (tenant_id, status, requested_at);
@@ -0,0 +1,30 @@
+-- Agent Service: kuyruk taşması/mesai dışı akışlarında bırakılan sesli mesajlar (ajan gelen kutusu).
+-- Satır kayıt başlarken RECORDING durumunda açılır, call.recording.available ile kayıt bağlanıp NEW olur.
+-- conversations başka bir servise aittir; yabancı anahtar yalnızca tablo varsa eklenir.
+CREATE TABLE IF NOT EXISTS voicemails (
+    id              BIGSERIAL   PRIMARY KEY,
+    conversation_id UUID        NOT NULL,
+    call_id         TEXT        NOT NULL,
+    tenant_id       TEXT        NOT NULL,
+    queue_id        TEXT,
+    caller_uri      TEXT,
+    -- RECORDING | NEW
+    status          TEXT        NOT NULL DEFAULT 'RECORDING',
+    bucket          TEXT,
+    recording_uri   TEXT,
+    public_url      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    recorded_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_voicemails_tenant_status ON voicemails (tenant_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_voicemails_call_id ON voicemails (call_id);
+
+DO $$
+BEGIN
+    IF to_regclass('conversations') IS NOT NULL
+        AND NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'voicemails_conversation_id_fkey') THEN
+        ALTER TABLE voicemails ADD CONSTRAINT voicemails_conversation_id_fkey
+            FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE;
+    END IF;
+END $$;
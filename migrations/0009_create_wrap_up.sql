@@ -0,0 +1,26 @@
+-- Agent Service: görüşme sonrası çalışma (wrap-up) ayarları, sonuç kodları ve konuşmaya yazılan sonuç.
+CREATE TABLE IF NOT EXISTS tenant_wrap_up_settings (
+    tenant_id        TEXT    PRIMARY KEY,
+    duration_seconds INTEGER NOT NULL CHECK (duration_seconds >= 0)
+);
+
+-- Tenant'ın tanımlı sonuç kodları. Kod tanımlamamış tenant'larda her kod kabul edilir.
+CREATE TABLE IF NOT EXISTS disposition_codes (
+    tenant_id TEXT    NOT NULL,
+    code      TEXT    NOT NULL,
+    label     TEXT    NOT NULL,
+    active    BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (tenant_id, code)
+);
+
+-- conversations başka bir servise aittir; kolonlar yalnızca tablo varsa eklenir.
+DO $$
+BEGIN
+    IF to_regclass('conversations') IS NOT NULL THEN
+        ALTER TABLE conversations
+            ADD COLUMN IF NOT EXISTS agent_id          TEXT,
+            ADD COLUMN IF NOT EXISTS disposition_code  TEXT,
+            ADD COLUMN IF NOT EXISTS disposition_notes TEXT,
+            ADD COLUMN IF NOT EXISTS wrapped_up_at     TIMESTAMPTZ;
+    END IF;
+END $$;
@@ -0,0 +1,9 @@
+-- Agent Service: ajan yetenekleri ve yetkinlik seviyeleri (1 = başlangıç, 5 = uzman).
+-- Dil yetenekleri de aynı tabloda dil kodu ile tutulur (ör. "tr", "en").
+CREATE TABLE IF NOT EXISTS agent_skills (
+    tenant_id   TEXT     NOT NULL,
+    agent_id    TEXT     NOT NULL,
+    skill       TEXT     NOT NULL,
+    proficiency SMALLINT NOT NULL CHECK (proficiency BETWEEN 1 AND 5),
+    PRIMARY KEY (tenant_id, agent_id, skill)
+);
@@ -0,0 +1,17 @@
+-- Agent Service: kuyruk istatistiklerinin 15 dakikalık aralık özetleri (geçmiş raporlama).
+-- Oranlar (ASA, terk oranı, hizmet seviyesi) toplamlardan hesaplanır; aralıklar böylece gün/hafta olarak toplanabilir.
+CREATE TABLE IF NOT EXISTS queue_interval_stats (
+    tenant_id             TEXT        NOT NULL,
+    queue_id              TEXT        NOT NULL,
+    interval_start        TIMESTAMPTZ NOT NULL,
+    offered               INTEGER     NOT NULL DEFAULT 0,
+    answered              INTEGER     NOT NULL DEFAULT 0,
+    abandoned             INTEGER     NOT NULL DEFAULT 0,
+    answered_within_sl    INTEGER     NOT NULL DEFAULT 0,
+    answer_wait_ms_total  BIGINT      NOT NULL DEFAULT 0,
+    abandon_wait_ms_total BIGINT      NOT NULL DEFAULT 0,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, queue_id, interval_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_interval_stats_tenant_time ON queue_interval_stats (tenant_id, interval_start);
@@ -0,0 +1,7 @@
+-- Agent Service: kuyruğa özel ayarlar. Satırı olmayan kuyruk servis varsayılanlarını kullanır.
+CREATE TABLE IF NOT EXISTS queue_settings (
+    tenant_id             TEXT    NOT NULL,
+    queue_id              TEXT    NOT NULL,
+    offer_timeout_seconds INTEGER CHECK (offer_timeout_seconds > 0),
+    PRIMARY KEY (tenant_id, queue_id)
+);
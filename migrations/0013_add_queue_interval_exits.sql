@@ -0,0 +1,5 @@
+-- Agent Service: kuyruktan cevaplanmadan ve terk edilmeden çıkışlar (zaman aşımı, geri arama, sesli mesaj).
+ALTER TABLE queue_interval_stats
+    ADD COLUMN IF NOT EXISTS timed_out  INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS callbacks  INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS voicemails INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- Agent Service: echo testi uçtan uca trunk RTT'sini değil, media-service'in her geri basılan parça için verdiği
+-- onayın gecikmesini ölçer. Kolonlar ölçülen şeyin adıyla yeniden adlandırılır.
+ALTER TABLE echo_test_diagnostics RENAME COLUMN latency_ms TO media_ack_ms;
+ALTER TABLE echo_test_diagnostics RENAME COLUMN jitter_ms TO media_ack_jitter_ms;
+ALTER TABLE echo_test_diagnostics RENAME COLUMN min_rtt_ms TO min_media_ack_ms;
+ALTER TABLE echo_test_diagnostics RENAME COLUMN max_rtt_ms TO max_media_ack_ms;
+
+COMMENT ON COLUMN echo_test_diagnostics.media_ack_ms IS 'Parçanın okunmasından media-service onayına kadar geçen ortalama süre (ms); trunk RTT değildir.';
+COMMENT ON COLUMN echo_test_diagnostics.media_ack_jitter_ms IS 'Ardışık onay gecikmeleri arasındaki ortalama mutlak fark (ms).';
@@ -0,0 +1,8 @@
+// Package migrations, servisin SQL şema değişikliklerini binary'ye gömer. Dosyalar sürüm sırasıyla
+// (dosya adının önekine göre) uygulanır; uygulananlar schema_migrations tablosunda tutulur.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS